@@ -1,5 +1,12 @@
 package binpacking
 
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
 // Algorithm types of algorithms supported by the library
 type Algorithm int
 
@@ -22,25 +29,262 @@ const (
 	BinCompletion
 	// ModifiedFirstFitDecreasing the modified first fit decreasing algorithm invented by Johnson and Garey
 	ModifiedFirstFitDecreasing
+	// NextFitDecreasing first sorts objects by size (decreasing) and then applies NextFit
+	NextFitDecreasing
+	// WorstFit puts objects in the emptiest bin that can fit them
+	WorstFit
+	// WorstFitDecreasing first sorts objects by size (decreasing) and then applies WorstFit
+	WorstFitDecreasing
+	// AlmostWorstFit puts objects in the second emptiest bin that can fit them
+	AlmostWorstFit
+	// Harmonic packs objects of similar size together, keeping at most K bins open
+	Harmonic
+	// RefinedHarmonic the Harmonic variant by Lee and Lee which pairs some objects over a half and a third
+	RefinedHarmonic
+	// ModifiedHarmonic the Harmonic variant by Ramanan et al. which also fills objects over a half with smaller ones
+	ModifiedHarmonic
+	// MartelloToth the MTP branch-and-bound algorithm by Martello and Toth, which proves optimality
+	MartelloToth
+	// HybridGroupingGenetic Falkenauer's hybrid grouping genetic algorithm, which evolves packings bin by bin
+	HybridGroupingGenetic
+	// SimulatedAnnealing moves objects between bins at random, taking fewer worse moves as it cools
+	SimulatedAnnealing
+	// TabuSearch makes the best move of objects between bins, forbidding recently undone moves
+	TabuSearch
+	// ColumnGeneration rounds the LP relaxation of the cutting stock model, then proves optimality with MTP
+	ColumnGeneration
+	// DSatur packs first the objects kept out of the most bins by conflicts, as in Brélaz's graph coloring
+	DSatur
 )
 
-var names = []string{
-	"Unknown",
-	"NextFit",
-	"FirstFit",
-	"FirstFitDecreasing",
-	"BestFit",
-	"BestFitDecreasing",
-	"PackingConstraint",
-	"BinCompletion",
-	"ModifiedFirstFitDecreasing"}
+// Packer a strategy used to solve an instance of the bin packing problem.
+// New strategies can be made available by name using Register.
+// Items are given in the caller's order, so packers which know where each
+// item came from may record it with Bin.PackIndex; any item packed with
+// Bin.Pack is matched back to an input position of the same size afterwards.
+type Packer interface {
+	// PackAll pack every item into the bins of the collection,
+	// stopping early if the context is done
+	PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error
+}
+
+// PackerFunc adapter allowing an ordinary function to be used as a Packer
+type PackerFunc func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error
+
+// PackAll call the underlying function
+func (packer PackerFunc) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	return packer(ctx, binCollection, items)
+}
+
+// IncrementalPacker a Packer which can also place a single item,
+// used by BinCollectionImpl.PackItem
+type IncrementalPacker interface {
+	Packer
+	PackItem(binCollection *BinCollectionImpl, item Item)
+}
+
+// ItemPacker a Packer for algorithms which place items one at a time,
+// like NextFitPack or BestFitPack
+type ItemPacker struct {
+	// Pack pack a single item into the collection
+	Pack func(binCollection *BinCollectionImpl, item Item)
+	// Decreasing sort items by size (decreasing) before packing them
+	Decreasing bool
+}
+
+// PackAll pack every item in succession
+func (packer ItemPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	if packer.Decreasing {
+		items = items.SortedDecreasing()
+	}
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		packer.PackItem(binCollection, item)
+	}
+	return nil
+}
+
+// PackItem pack a single item, creating the first bin if necessary
+func (packer ItemPacker) PackItem(binCollection *BinCollectionImpl, item Item) {
+	if binCollection.GetTotalBins() == 0 {
+		binCollection.NewBin()
+	}
+	packer.Pack(binCollection, item)
+}
+
+var (
+	registryLock sync.RWMutex
+	names        = []string{
+		"Unknown",
+		"NextFit",
+		"FirstFit",
+		"FirstFitDecreasing",
+		"BestFit",
+		"BestFitDecreasing",
+		"PackingConstraint",
+		"BinCompletion",
+		"ModifiedFirstFitDecreasing",
+		"NextFitDecreasing",
+		"WorstFit",
+		"WorstFitDecreasing",
+		"AlmostWorstFit",
+		"Harmonic",
+		"RefinedHarmonic",
+		"ModifiedHarmonic",
+		"MartelloToth",
+		"HybridGroupingGenetic",
+		"SimulatedAnnealing",
+		"TabuSearch",
+		"ColumnGeneration",
+		"DSatur"}
+	packers []Packer
+)
+
+func init() {
+	packers = []Packer{
+		nil,
+		ItemPacker{Pack: NextFitPack},
+		firstFitPacker{},
+		firstFitPacker{decreasing: true},
+		bestFitPacker{},
+		bestFitPacker{decreasing: true},
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			return binCollection.PackAllConstraint(ctx, items)
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllBinCompletion(ctx, items.SortedDecreasing())
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			return binCollection.PackAllMFFD(ctx, items.SortedDecreasing())
+		}),
+		ItemPacker{Pack: NextFitDecreasingPack, Decreasing: true},
+		ItemPacker{Pack: WorstFitPack},
+		ItemPacker{Pack: WorstFitDecreasingPack, Decreasing: true},
+		ItemPacker{Pack: AlmostWorstFitPack},
+		harmonicPacker{variant: harmonic},
+		harmonicPacker{variant: refinedHarmonic},
+		harmonicPacker{variant: modifiedHarmonic},
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllMTP(ctx, items)
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllGenetic(ctx, items)
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllAnnealing(ctx, items)
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllTabu(ctx, items)
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			binCollection.PackAllColumnGeneration(ctx, items)
+			return nil
+		}),
+		PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+			return binCollection.PackAllDSatur(ctx, items)
+		})}
+	// each built-in algorithm can be followed by Improve
+	for algorithm, count := NextFit, len(names); int(algorithm) < count; algorithm++ {
+		registerImproved(algorithm)
+	}
+}
+
+// Register make a packing strategy available under the given name,
+// returning the Algorithm used to select it. Registering an existing
+// name replaces the strategy used by that Algorithm. The name followed
+// by "+LS" is registered as well, unless it already ends that way.
+func Register(name string, packer Packer) Algorithm {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	algorithm := register(name, packer)
+	if !strings.HasSuffix(name, improvementSuffix) {
+		registerImproved(algorithm)
+	}
+	return algorithm
+}
+
+// registerImproved register the name of an algorithm followed by "+LS" as
+// that algorithm followed by Improve, with the registry already locked
+func registerImproved(base Algorithm) {
+	register(names[base]+improvementSuffix, improvingPacker{base: base})
+}
+
+// register make a packing strategy available under the given name,
+// with the registry already locked
+func register(name string, packer Packer) Algorithm {
+	for i, registered := range names {
+		if registered == name {
+			packers[i] = packer
+			return Algorithm(i)
+		}
+	}
+	names = append(names, name)
+	packers = append(packers, packer)
+	return Algorithm(len(names) - 1)
+}
+
+// Algorithms get the names of every registered algorithm
+func Algorithms() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	registered := make([]string, len(names)-1)
+	copy(registered, names[1:])
+	return registered
+}
+
+// Packer get the packing strategy registered for this algorithm, nil if there is none
+func (algorithm Algorithm) Packer() Packer {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if algorithm < 0 || int(algorithm) >= len(packers) {
+		return nil
+	}
+	return packers[algorithm]
+}
 
 func (algorithm Algorithm) String() string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if algorithm < 0 || int(algorithm) >= len(names) {
+		return names[Unknown]
+	}
 	return names[algorithm]
 }
 
-// GetAlgorithm get an algorithm from string
+// MarshalJSON algorithms are written by name, since the number assigned
+// to a registered algorithm depends on the order of registration
+func (algorithm Algorithm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algorithm.String())
+}
+
+// UnmarshalJSON read an algorithm from either its name or its number
+func (algorithm *Algorithm) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*algorithm = GetAlgorithm(name)
+		return nil
+	}
+	var number int
+	if err := json.Unmarshal(b, &number); err != nil {
+		return err
+	}
+	*algorithm = Algorithm(number)
+	return nil
+}
+
+// GetAlgorithm get an algorithm from string, Unknown if none is registered
+// under it. The name of any registered algorithm followed by "+LS" selects
+// that algorithm followed by Improve.
 func GetAlgorithm(s string) Algorithm {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
 	for i, name := range names {
 		if name == s {
 			return Algorithm(i)
@@ -0,0 +1,77 @@
+package binpacking
+
+import (
+	"context"
+	"math"
+	"math/rand"
+)
+
+// defaultAnnealingIterations the number of moves simulated annealing tries by default
+const defaultAnnealingIterations = 200000
+
+// finalTemperature the fraction of the starting temperature reached by the last move
+const finalTemperature = 1e-3
+
+// PackAllAnnealing pack all items using simulated annealing over the bin
+// of each item, starting from first fit decreasing. Each step moves a random
+// item to a random bin, or swaps it with a random item in another bin, as
+// long as no bin overflows. Steps are rated by the change in the sum of the
+// squared bin loads, which rewards full bins and so leads towards emptying
+// the others. Better steps are always taken and worse ones with a chance
+// that shrinks as the temperature cools. Stops after the configured number
+// of iterations, when the time limit or context is done, or once the
+// packing reaches a lower bound, in which case the status is set to Optimal.
+func (binCollection *BinCollectionImpl) PackAllAnnealing(ctx context.Context, items Items) {
+	ctx, cancel := binCollection.withTimeLimit(ctx)
+	defer cancel()
+	iterations := binCollection.Iterations
+	if iterations <= 0 {
+		iterations = defaultAnnealingIterations
+	}
+	limits := binCollection.limits()
+	r := rand.New(rand.NewSource(binCollection.Seed))
+	state := newAssignment(items, limits)
+	floor := LowerBoundCardinality(items, limits.capacity, limits.maxItems)
+	best, bestBins := state.snapshot(), state.used
+	binCollection.reportBest(bestBins)
+
+	// start where moving a quarter of a bin between bins a half apart
+	// is accepted with a chance of about 1/e
+	temperature := float64(limits.capacity) * float64(limits.capacity) / 8
+	cooling := math.Pow(finalTemperature, 1/float64(iterations))
+	for iteration := 0; iteration < iterations && bestBins > floor; iteration++ {
+		if iteration%1024 == 0 && ctx.Err() != nil {
+			break
+		}
+		temperature *= cooling
+		item := r.Intn(len(items))
+		if r.Intn(2) == 0 {
+			to := r.Intn(len(state.loads))
+			if !state.canMove(item, to) || !accept(r, state.moveDelta(item, to), temperature) {
+				continue
+			}
+			state.move(item, to)
+		} else {
+			other := r.Intn(len(items))
+			if !state.canSwap(item, other) || !accept(r, state.swapDelta(item, other), temperature) {
+				continue
+			}
+			state.swap(item, other)
+		}
+		if state.used < bestBins {
+			best, bestBins = state.snapshot(), state.used
+			binCollection.reportBest(bestBins)
+		}
+	}
+
+	binCollection.setAssignment(items, best)
+	if bestBins <= floor {
+		binCollection.Status = Optimal
+	}
+}
+
+// accept the Metropolis criterion: always take a step which does not make
+// things worse, and a worse one with probability exp(delta/temperature)
+func accept(r *rand.Rand, delta Size, temperature float64) bool {
+	return delta >= 0 || r.Float64() < math.Exp(float64(delta)/temperature)
+}
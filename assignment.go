@@ -0,0 +1,112 @@
+package binpacking
+
+import "context"
+
+// assignment a packing represented as the bin of each item, as in
+// PackAllConstraint, which the metaheuristics change one move at a time
+type assignment struct {
+	items  Items
+	limits binLimits
+	bin    []int  // bin of each item
+	loads  []Size // usage of each bin
+	counts []int  // number of items in each bin
+	used   Count  // number of bins holding at least one item
+}
+
+// newAssignment start from the first fit decreasing packing of the items
+func newAssignment(items Items, limits binLimits) *assignment {
+	state := &assignment{items: items, limits: limits, bin: make([]int, len(items))}
+	initial := &BinCollectionImpl{BinCapacity: limits.capacity, Bins: make(Bins, 0), MaxItems: limits.maxItems}
+	firstFitPacker{decreasing: true}.PackAll(context.Background(), initial, items)
+	for i, bin := range initial.Bins {
+		for _, position := range bin.Indices {
+			state.bin[position] = i
+		}
+		state.loads = append(state.loads, bin.Usage)
+		state.counts = append(state.counts, len(bin.Items))
+	}
+	state.used = Count(len(state.loads))
+	return state
+}
+
+// moveDelta change in the sum of squared loads from moving an item to a bin
+func (state *assignment) moveDelta(item, to int) Size {
+	size := Size(state.items[item])
+	from := state.bin[item]
+	return 2 * size * (state.loads[to] - state.loads[from] + size)
+}
+
+// swapDelta change in the sum of squared loads from swapping two items
+// between their bins
+func (state *assignment) swapDelta(first, second int) Size {
+	difference := Size(state.items[second]) - Size(state.items[first]) // moved into the first item's bin
+	a, b := state.loads[state.bin[first]], state.loads[state.bin[second]]
+	return 2 * difference * (a - b + difference)
+}
+
+// canMove whether an item can move to a bin already in use without overfilling it
+func (state *assignment) canMove(item, to int) bool {
+	return to != state.bin[item] && state.loads[to] > 0 &&
+		state.limits.fits(state.loads[to]+Size(state.items[item]), state.counts[to]+1)
+}
+
+// canSwap whether two items in different bins can swap without overfilling either
+func (state *assignment) canSwap(first, second int) bool {
+	a, b := state.bin[first], state.bin[second]
+	difference := Size(state.items[second]) - Size(state.items[first])
+	return a != b && state.loads[a]+difference <= state.limits.capacity && state.loads[b]-difference <= state.limits.capacity
+}
+
+// move put an item in another bin
+func (state *assignment) move(item, to int) {
+	size := Size(state.items[item])
+	from := state.bin[item]
+	state.loads[from] -= size
+	state.loads[to] += size
+	state.counts[from]--
+	state.counts[to]++
+	state.bin[item] = to
+	if state.loads[from] == 0 {
+		state.used--
+	}
+}
+
+// swap exchange the bins of two items
+func (state *assignment) swap(first, second int) {
+	difference := Size(state.items[second]) - Size(state.items[first])
+	state.loads[state.bin[first]] += difference
+	state.loads[state.bin[second]] -= difference
+	state.bin[first], state.bin[second] = state.bin[second], state.bin[first]
+}
+
+// snapshot copy the bin of each item
+func (state *assignment) snapshot() []int {
+	bins := make([]int, len(state.bin))
+	copy(bins, state.bin)
+	return bins
+}
+
+// setAssignment replace the collection's bins with the packing given
+// by the bin of each item, leaving out bins with no items
+func (binCollection *BinCollectionImpl) setAssignment(items Items, assigned []int) {
+	bins := make([]*lsBin, 0)
+	renumbered := make(map[int]int)
+	for position, item := range items {
+		index, ok := renumbered[assigned[position]]
+		if !ok {
+			index = len(bins)
+			renumbered[assigned[position]] = index
+			bins = append(bins, &lsBin{})
+		}
+		bins[index].items = append(bins[index].items, lsItem{Size(item), position})
+		bins[index].load += Size(item)
+	}
+	binCollection.setBins(bins)
+}
+
+// reportBest tell the improvement callback about a new best bin count, if there is a callback
+func (binCollection *BinCollectionImpl) reportBest(bins Count) {
+	if binCollection.OnImprovement != nil {
+		binCollection.OnImprovement(bins)
+	}
+}
@@ -1,24 +1,24 @@
 package binpacking
 
+import "context"
+
 // BestFitPack pack a single item using the best fit algorithm
 func BestFitPack(binCollection *BinCollectionImpl, item Item) {
 
 	// find the bin with the smallest non-negative remaining space after adding the item
-	smallestRemainderIndex := 0
+	smallestRemainderIndex := -1
 	var smallestRemainder Size
 	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
 		// remainder = the amount of space left over after adding the item
-		remainder := binCollection.GetBin(i).Remaining() - Size(item)
-		if i == 0 {
-			smallestRemainder = remainder
-		} else if remainder >= 0 && (remainder < smallestRemainder || smallestRemainder < 0) {
+		remainder := binCollection.binAt(i).Remaining() - Size(item)
+		if binCollection.binAt(i).CanFit(item) && (smallestRemainderIndex < 0 || remainder < smallestRemainder) {
 			smallestRemainder = remainder
 			smallestRemainderIndex = i
 		}
 	}
 	// if we found a bin that the item will fit inside
-	if smallestRemainder >= 0 {
-		remainderBin := binCollection.GetBin(smallestRemainderIndex)
+	if smallestRemainderIndex >= 0 {
+		remainderBin := binCollection.binAt(smallestRemainderIndex)
 		remainderBin.Pack(item)
 	} else {
 		// create a new bin
@@ -32,3 +32,47 @@ func BestFitPack(binCollection *BinCollectionImpl, item Item) {
 func BestFitDecreasingPack(binCollection *BinCollectionImpl, item Item) {
 	BestFitPack(binCollection, item)
 }
+
+// bestFitPacker packs all items using best fit, finding the tightest bin
+// with enough room through a balanced ordered multiset in O(log n) per item
+type bestFitPacker struct {
+	decreasing bool
+}
+
+// PackAll pack every item in succession
+func (packer bestFitPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	order := items.inputOrder()
+	if packer.decreasing {
+		order = items.decreasingOrder()
+	}
+	treap := &binTreap{}
+	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
+		if room := binCollection.binAt(i).room(); room > 0 {
+			treap.Insert(room, i)
+		}
+	}
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item := items[position]
+		index := treap.Ceiling(Size(item))
+		if index < 0 {
+			binCollection.NewBin()
+			index = int(binCollection.GetTotalBins()) - 1
+		} else {
+			treap.Delete(binCollection.binAt(index).room(), index)
+		}
+		bin := binCollection.binAt(index)
+		bin.PackIndex(item, position)
+		if room := bin.room(); room > 0 {
+			treap.Insert(room, index)
+		}
+	}
+	return nil
+}
+
+// PackItem pack a single item using the linear scan in BestFitPack
+func (packer bestFitPacker) PackItem(binCollection *BinCollectionImpl, item Item) {
+	BestFitPack(binCollection, item)
+}
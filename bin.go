@@ -4,7 +4,14 @@ package binpacking
 type Bin struct {
 	Capacity Size `json:"capacity"`
 	Items    `json:"items"`
-	Usage    Size `json:"usage"`
+	// Indices the position of each item in the input passed to PackAll
+	Indices []int `json:"indices"`
+	Usage   Size  `json:"usage"`
+	// Type the position of the bin's type in the BinTypes it was packed
+	// with, when packing bins of several types
+	Type int `json:"type,omitempty"`
+	// MaxItems the most items the bin may hold, unlimited when zero
+	MaxItems Count `json:"maxItems,omitempty"`
 }
 
 // Bins collection type for Bin
@@ -12,7 +19,7 @@ type Bins []Bin
 
 // NewBin create a new bin
 func NewBin(size Size) Bin {
-	return Bin{size, make(Items, 0), 0}
+	return Bin{Capacity: size, Items: make(Items, 0), Indices: make([]int, 0)}
 }
 
 // Remaining get the amount of remaining space in this bin
@@ -22,7 +29,22 @@ func (bin *Bin) Remaining() Size {
 
 // CanFit check if the given bin can fit an item
 func (bin *Bin) CanFit(item Item) bool {
-	return bin.Remaining() >= Size(item)
+	return bin.Remaining() >= Size(item) && bin.hasSlots(1)
+}
+
+// hasSlots whether the bin can take the given number of items more without
+// going over MaxItems, whatever their size
+func (bin *Bin) hasSlots(count int) bool {
+	return bin.MaxItems <= 0 || Count(len(bin.Items)+count) <= bin.MaxItems
+}
+
+// room the largest item the bin can still fit, zero once it holds MaxItems
+// items, used to index bins by the room they have left
+func (bin *Bin) room() Size {
+	if !bin.hasSlots(1) {
+		return 0
+	}
+	return bin.Remaining()
 }
 
 // Pack adds an item to a Bin
@@ -30,3 +52,20 @@ func (bin *Bin) Pack(item Item) {
 	bin.Items = append(bin.Items, item)
 	bin.Usage += Size(item) // update bin capacity
 }
+
+// unpack take out the item at the given place in the bin, with its index
+func (bin *Bin) unpack(j int) {
+	bin.Usage -= Size(bin.Items[j])
+	bin.Items = append(bin.Items[:j], bin.Items[j+1:]...)
+	if j < len(bin.Indices) {
+		bin.Indices = append(bin.Indices[:j], bin.Indices[j+1:]...)
+	}
+}
+
+// PackIndex adds an item to a Bin, recording its position in the input.
+// Algorithms which pack some items this way must do so before any
+// of the bin's items are packed without an index.
+func (bin *Bin) PackIndex(item Item, index int) {
+	bin.Pack(item)
+	bin.Indices = append(bin.Indices, index)
+}
@@ -1,42 +1,45 @@
 package binpacking
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"math"
 	"sort"
 )
 
 // BinCollection an interface representing an instance
 // of the bin packing problem.
 type BinCollection interface {
-	PackAll(items Items)
+	PackAll(items Items) error
+	PackAllContext(ctx context.Context, items Items) error
 	GetTotalBins() Count
 	GetBinCapacity() Size
 	SetTime(nanoseconds int64)
 	String() string
 }
 
+// Solution a solved packing problem of any kind, whether of items, demands,
+// vectors, rectangles or boxes. Each writes its number of bins as "count",
+// with "algorithm", "status" and "solution_time", in the JSON from String,
+// so results of every kind can be handled alike.
+type Solution interface {
+	GetTotalBins() Count
+	SetTime(nanoseconds int64)
+	String() string
+}
+
 // NewBinCollection create an instance of the bin packing problem
 // from a PackingList object
 func NewBinCollection(pList *PackingList) BinCollection {
 
-	collection := &BinCollectionImpl{
+	return &BinCollectionImpl{
 		BinCapacity: pList.Size,
 		TotalBins:   0,
 		Bins:        make(Bins, 0), // pre-allocate memory for a reasonably large capacity
-		Algorithm:   pList.Algorithm}
-
-	// init bins for packing constraint
-	if pList.Algorithm == PackingConstraint {
-		roundedLowerBound := int(math.Round(float64(pList.LowerBound) * 1.2))
-		for i := 0; i < roundedLowerBound; i++ {
-			collection.NewBin() // add new bins for all of them
-		}
-	} else if pList.Algorithm != ModifiedFirstFitDecreasing {
-		collection.NewBin() // always create first bin if not MFFD or constraint
-	}
-	return collection
+		Algorithm:   pList.Algorithm,
+		BinTypes:    pList.BinTypes,
+		Conflicts:   pList.Conflicts,
+		MaxItems:    pList.MaxItems,
+		Options:     pList.Options}
 
 }
 
@@ -46,8 +49,26 @@ type BinCollectionImpl struct {
 	BinCapacity  Size  `json:"capacity"`
 	TotalBins    Count `json:"count"`
 	Bins         `json:"bins"`
-	Algorithm    `json:"algorithm"`
-	SolutionTime int64 `json:"solution_time"`
+	Algorithm    Algorithm `json:"algorithm"`
+	Status       Status    `json:"status"`
+	SolutionTime int64     `json:"solution_time"`
+	// MaxOpenBins the most bins a bounded-space algorithm kept open at once
+	MaxOpenBins Count `json:"maxOpenBins,omitempty"`
+	// Nodes the number of search tree nodes explored by an exact algorithm
+	Nodes int64 `json:"nodes,omitempty"`
+	// BinTypes the kinds of bin to choose from, minimizing their total
+	// cost rather than the number of bins, when not all bins are the same
+	BinTypes BinTypes `json:"binTypes,omitempty"`
+	// TotalCost the total cost of the bins, when packing bins of several types
+	TotalCost float64 `json:"totalCost,omitempty"`
+	// Conflicts pairs of items, by their positions in the input, which
+	// may not share a bin, kept apart by only some algorithms
+	Conflicts Conflicts `json:"conflicts,omitempty"`
+	// MaxItems the most items any bin may hold, unlimited when zero
+	MaxItems Count `json:"maxItems,omitempty"`
+	Options
+	input    Items          // items passed to PackAll, in their original order
+	harmonic *harmonicState // bins a Harmonic algorithm left open, for PackItem
 }
 
 // GetTotalBins getter for the total number of bins
@@ -55,6 +76,12 @@ func (binCollection *BinCollectionImpl) GetTotalBins() Count {
 	return binCollection.TotalBins
 }
 
+// GetInput getter for the items most recently passed to PackAll, in their
+// original order. Bin.Indices are positions in this slice.
+func (binCollection *BinCollectionImpl) GetInput() Items {
+	return binCollection.input
+}
+
 // GetBinCapacity getter for the individual bin capacities
 func (binCollection *BinCollectionImpl) GetBinCapacity() Size {
 	return binCollection.BinCapacity
@@ -63,30 +90,96 @@ func (binCollection *BinCollectionImpl) GetBinCapacity() Size {
 // NewBin method used for allocating a new bin when necessary.
 // returns the new bin just created
 func (binCollection *BinCollectionImpl) NewBin() *Bin {
-	binCollection.Bins = append(binCollection.Bins, NewBin(binCollection.BinCapacity))
+	bin := NewBin(binCollection.BinCapacity)
+	bin.MaxItems = binCollection.MaxItems
+	binCollection.Bins = append(binCollection.Bins, bin)
 	binCollection.TotalBins++ // update our number of bins used
 	return binCollection.GetLastBin()
 }
 
 // PackAll solve the underlying bin packing problem
-func (binCollection *BinCollectionImpl) PackAll(items Items) {
-	// reverse sort for algorithms that require it
-	if binCollection.Algorithm == FirstFitDecreasing ||
-		binCollection.Algorithm == BestFitDecreasing ||
-		binCollection.Algorithm == ModifiedFirstFitDecreasing ||
-		binCollection.Algorithm == PackingConstraint {
-		sort.Sort(sort.Reverse(items))
-	}
-	if binCollection.Algorithm == ModifiedFirstFitDecreasing {
-		binCollection.PackAllMFFD(items)
-	} else if binCollection.Algorithm == PackingConstraint {
-		binCollection.PackAllConstraint(items)
-	} else {
-		for _, item := range items {
-			binCollection.PackItem(item)
+// using the Packer registered for the collection's algorithm
+func (binCollection *BinCollectionImpl) PackAll(items Items) error {
+	return binCollection.PackAllContext(context.Background(), items)
+}
+
+// PackAllContext solve the underlying bin packing problem, giving up
+// when the context is done. Exact solvers stopped this way keep the best
+// packing found so far and report a NotProvenOptimal status instead of an error.
+// When BinTypes are given, bins of those types are packed at the least total
+// cost instead, which only some algorithms support (see VariableSizedAlgorithms).
+// When Conflicts are given, the items in each are kept in different bins, which
+// only some algorithms support (see ConflictAlgorithms), and none with BinTypes,
+// and Reduce is ignored.
+// Reduce is also ignored when MaxItems limits the number of items in a bin.
+func (binCollection *BinCollectionImpl) PackAllContext(ctx context.Context, items Items) error {
+	if len(binCollection.BinTypes) > 0 {
+		return binCollection.packVariableSized(ctx, items)
+	}
+	packer := binCollection.Algorithm.Packer()
+	if len(binCollection.Conflicts) > 0 {
+		packer = conflictPackers[binCollection.Algorithm]
+	}
+	if packer == nil {
+		return &UnknownAlgorithmError{Algorithm: binCollection.Algorithm}
+	}
+	if binCollection.BinCapacity <= 0 {
+		return &InvalidCapacityError{Capacity: binCollection.BinCapacity}
+	}
+	if binCollection.MaxItems < 0 {
+		return &InvalidMaxItemsError{MaxItems: binCollection.MaxItems}
+	}
+	for i, item := range items {
+		if err := validateItem(i, item, binCollection.BinCapacity); err != nil {
+			return err
+		}
+	}
+	if err := binCollection.Conflicts.validate(len(items)); err != nil {
+		return err
+	}
+	// packers work on their own copy, so the caller's slice keeps its order
+	binCollection.input = make(Items, len(items))
+	copy(binCollection.input, items)
+	working := make(Items, len(items))
+	copy(working, items)
+	if binCollection.Reduce && len(binCollection.Conflicts) == 0 && binCollection.MaxItems == 0 {
+		if err := binCollection.packReduced(ctx, packer, working); err != nil {
+			return err
 		}
+	} else if err := packer.PackAll(ctx, binCollection, working); err != nil {
+		return err
 	}
 	binCollection.cleanupBins()
+	binCollection.assignIndices(binCollection.input)
+	if binCollection.Status == Unsolved {
+		binCollection.Status = Feasible
+	}
+	return nil
+}
+
+// PackSizers pack arbitrary objects, returning the objects placed in each bin
+// in the same order as the collection's bins
+func (binCollection *BinCollectionImpl) PackSizers(objects []Sizer) ([][]Sizer, error) {
+	return binCollection.PackSizersContext(context.Background(), objects)
+}
+
+// PackSizersContext pack arbitrary objects, giving up when the context is done
+func (binCollection *BinCollectionImpl) PackSizersContext(ctx context.Context, objects []Sizer) ([][]Sizer, error) {
+	items := make(Items, len(objects))
+	for i, object := range objects {
+		items[i] = Item(object.Size())
+	}
+	if err := binCollection.PackAllContext(ctx, items); err != nil {
+		return nil, err
+	}
+	placed := make([][]Sizer, len(binCollection.Bins))
+	for i, bin := range binCollection.Bins {
+		placed[i] = make([]Sizer, len(bin.Indices))
+		for j, index := range bin.Indices {
+			placed[i][j] = objects[index]
+		}
+	}
+	return placed, nil
 }
 
 // GetFirstBin getter for the first bin created
@@ -100,38 +193,40 @@ func (binCollection *BinCollectionImpl) GetLastBin() *Bin {
 }
 
 // GetBin get an element at the given index in our list of bins
-func (binCollection *BinCollectionImpl) GetBin(index int) *Bin {
+func (binCollection *BinCollectionImpl) GetBin(index int) (*Bin, error) {
+	if index < 0 || index >= len(binCollection.Bins) {
+		return nil, &BinIndexError{Index: index, TotalBins: binCollection.TotalBins}
+	}
+	return binCollection.binAt(index), nil
+}
+
+// binAt get the bin at an index already known to be in range
+func (binCollection *BinCollectionImpl) binAt(index int) *Bin {
 	return &binCollection.Bins[index]
 }
 
 // PackItem method used for packing each item in succession
-func (binCollection *BinCollectionImpl) PackItem(item Item) {
-	switch binCollection.Algorithm {
-	case NextFit:
-		NextFitPack(binCollection, item)
-	case FirstFit:
-		FirstFitPack(binCollection, item)
-	case FirstFitDecreasing:
-		FirstFitDecreasingPack(binCollection, item)
-	case BestFit:
-		BestFitPack(binCollection, item)
-	case BestFitDecreasing:
-		BestFitDecreasingPack(binCollection, item)
-	default:
-		// do nothing here. Algorithm not supported
-		panic(fmt.Errorf("unsupported algorithm for PackItem: %v", binCollection.Algorithm))
-	}
-}
-
-// Find find an item based on the given predicate, nil if not found
+func (binCollection *BinCollectionImpl) PackItem(item Item) error {
+	packer, ok := binCollection.Algorithm.Packer().(IncrementalPacker)
+	if !ok {
+		// algorithm is unknown or cannot pack items one at a time
+		return &UnknownAlgorithmError{Algorithm: binCollection.Algorithm}
+	}
+	if err := validateItem(-1, item, binCollection.BinCapacity); err != nil {
+		return err
+	}
+	packer.PackItem(binCollection, item)
+	return nil
+}
+
+// Find find the first bin matching the given predicate, nil if not found
 func (binCollection *BinCollectionImpl) Find(predicate func(*Bin) bool) *Bin {
-	var found *Bin
 	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
-		if predicate(binCollection.GetBin(i)) {
-			found = binCollection.GetBin(i)
+		if predicate(binCollection.binAt(i)) {
+			return binCollection.binAt(i)
 		}
 	}
-	return found
+	return nil
 }
 
 // String return representation of this object as a string
@@ -145,13 +240,58 @@ func (binCollection *BinCollectionImpl) SetTime(nanoseconds int64) {
 	binCollection.SolutionTime = nanoseconds
 }
 
+// assignIndices record the input position of every packed item which was
+// packed without one. Items of equal size are interchangeable, so each gets
+// the earliest position of that size not already taken.
+func (binCollection *BinCollectionImpl) assignIndices(input Items) {
+	complete := true
+	for _, bin := range binCollection.Bins {
+		complete = complete && len(bin.Indices) == len(bin.Items)
+	}
+	if complete {
+		return // every item was packed with its position
+	}
+	taken := make([]bool, len(input))
+	for _, bin := range binCollection.Bins {
+		for _, index := range bin.Indices {
+			taken[index] = true
+		}
+	}
+	// free input positions grouped by size, earliest first within each size
+	positions := make([]int, 0, len(input))
+	for i := range input {
+		if !taken[i] {
+			positions = append(positions, i)
+		}
+	}
+	sort.Slice(positions, func(a, b int) bool {
+		first, second := positions[a], positions[b]
+		return input[first] < input[second] || (input[first] == input[second] && first < second)
+	})
+	next := make(map[Item]int) // next free entry in positions for each size
+	for i := len(positions) - 1; i >= 0; i-- {
+		next[input[positions[i]]] = i
+	}
+	for i := range binCollection.Bins {
+		bin := binCollection.binAt(i)
+		for _, item := range bin.Items[len(bin.Indices):] {
+			position, ok := next[item]
+			if !ok || position >= len(positions) || input[positions[position]] != item {
+				break // not an input item, which Verify will report
+			}
+			bin.Indices = append(bin.Indices, positions[position])
+			next[item] = position + 1
+		}
+	}
+}
+
 // cleanupBins convenience method used to clean out unused bins from the list
 func (binCollection *BinCollectionImpl) cleanupBins() {
 
 	for {
 		emptyIndex := -1
 		for i := 0; i < int(binCollection.GetTotalBins()); i++ {
-			bin := binCollection.GetBin(i)
+			bin := binCollection.binAt(i)
 			if bin.Usage == 0 && len(bin.Items) == 0 {
 				// empty bin.
 				emptyIndex = i
@@ -0,0 +1,209 @@
+package binpacking
+
+import (
+	"context"
+	"sort"
+)
+
+// PackAllBinCompletion pack all items using Richard E. Korf's bin completion
+// algorithm. This is a branch-and-bound search over undominated bin
+// completions which returns a provably optimal packing.
+// If the context is done before the search finishes, the best packing
+// found so far is used and the status is set to NotProvenOptimal.
+// When MaxItems is set, completions have at most that many items, and a set
+// is only dominated by another with as many items or by adding an item.
+// Items are expected to be sorted in decreasing order.
+func (binCollection *BinCollectionImpl) PackAllBinCompletion(ctx context.Context, items Items) {
+	binCollection.Status = Optimal
+	if len(items) == 0 {
+		return
+	}
+
+	// use first fit decreasing as the initial upper bound on the solution
+	incumbent := &BinCollectionImpl{
+		BinCapacity: binCollection.BinCapacity,
+		Bins:        make(Bins, 0),
+		Algorithm:   FirstFitDecreasing,
+		MaxItems:    binCollection.MaxItems}
+	incumbent.NewBin()
+	for _, item := range items {
+		FirstFitDecreasingPack(incumbent, item)
+	}
+	search := &binCompletionSearch{
+		ctx:      ctx,
+		capacity: binCollection.BinCapacity,
+		maxItems: binCollection.MaxItems,
+		best:     make([]Items, 0, incumbent.GetTotalBins())}
+	search.floor = search.lowerBound(items)
+	for i := 0; i < int(incumbent.GetTotalBins()); i++ {
+		search.best = append(search.best, incumbent.binAt(i).Items)
+	}
+
+	// only search when first fit decreasing has not already met the lower bound
+	if Count(len(search.best)) > search.floor {
+		search.complete(items, make([]Items, 0, len(search.best)))
+	}
+	if ctx.Err() != nil {
+		binCollection.Status = NotProvenOptimal
+	}
+
+	for _, binItems := range search.best {
+		bin := binCollection.NewBin()
+		for _, item := range binItems {
+			bin.Pack(item)
+		}
+	}
+}
+
+// binCompletionSearch state of a single bin completion search
+type binCompletionSearch struct {
+	ctx      context.Context
+	capacity Size
+	maxItems Count   // most items in a bin, unlimited when zero
+	best     []Items // best solution found so far
+	floor    Count   // lower bound for the whole problem
+}
+
+// lowerBound lower bound on the number of bins needed for the given items
+func (search *binCompletionSearch) lowerBound(items Items) Count {
+	bound := CalculateLowerBound(items, search.capacity)
+	if search.maxItems > 0 {
+		if cardinality := LowerBoundCardinality(items, search.capacity, search.maxItems); cardinality > bound {
+			bound = cardinality
+		}
+	}
+	return bound
+}
+
+// complete recursively fill a bin containing the largest remaining item
+// with each of its undominated completions, pruning any branch that cannot
+// improve on the best solution found so far
+func (search *binCompletionSearch) complete(remaining Items, bins []Items) {
+	if len(remaining) == 0 {
+		if len(bins) < len(search.best) {
+			search.best = make([]Items, len(bins))
+			copy(search.best, bins)
+		}
+		return
+	}
+	if search.ctx.Err() != nil {
+		return // out of time, keep the incumbent
+	}
+	if len(bins)+int(search.lowerBound(remaining)) >= len(search.best) {
+		return // cannot improve on the incumbent
+	}
+
+	largest := remaining[0]
+	rest := remaining[1:]
+	for _, completion := range undominatedCompletions(search.ctx, largest, rest, search.capacity, search.maxItems) {
+		bin := Items{largest}
+		used := make([]bool, len(rest))
+		for _, index := range completion {
+			bin = append(bin, rest[index])
+			used[index] = true
+		}
+		next := make(Items, 0, len(rest)-len(completion))
+		for i, item := range rest {
+			if !used[i] {
+				next = append(next, item)
+			}
+		}
+		search.complete(next, append(bins, bin))
+		if Count(len(search.best)) <= search.floor || search.ctx.Err() != nil {
+			return // the incumbent meets the lower bound, or we are out of time
+		}
+	}
+}
+
+// undominatedCompletions generate every feasible set of items from rest
+// (sorted in decreasing order) that can share a bin with the largest item
+// without being dominated by another feasible set.
+// Completions are returned as indices into rest, ordered by decreasing sum,
+// and hold fewer than maxItems items when that is set.
+// Generation stops early once the context is done.
+func undominatedCompletions(ctx context.Context, largest Item, rest Items, capacity Size, maxItems Count) [][]int {
+	completions := make([][]int, 0)
+	sums := make([]Size, 0)
+	chosen := make([]int, 0)
+
+	var generate func(start int, residual Size)
+	generate = func(start int, residual Size) {
+		full := maxItems > 0 && Count(len(chosen)+1) >= maxItems
+		if !isDominatedCompletion(chosen, rest, residual, full, maxItems > 0) {
+			completion := make([]int, len(chosen))
+			copy(completion, chosen)
+			completions = append(completions, completion)
+			sums = append(sums, capacity-residual)
+		}
+		for i := start; i < len(rest) && !full && ctx.Err() == nil; i++ {
+			if Size(rest[i]) > residual {
+				continue
+			}
+			if i > start && rest[i] == rest[i-1] {
+				continue // equal items produce identical completions
+			}
+			chosen = append(chosen, i)
+			generate(i+1, residual-Size(rest[i]))
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	generate(0, capacity-Size(largest))
+
+	order := make([]int, len(completions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sums[order[i]] > sums[order[j]] })
+	sorted := make([][]int, len(completions))
+	for i, index := range order {
+		sorted[i] = completions[index]
+	}
+	return sorted
+}
+
+// isDominatedCompletion check whether a feasible set is dominated, meaning
+// that an excluded item could be added to it, or could replace one or two
+// of its items with a single item at least as large as their sum.
+// Larger replacement subsets are not checked, which only costs extra branching.
+// When the bin is full no item can be added, and when bins hold a limited
+// number of items two items are not replaced by one, since swapping them
+// would put both into the bin the one came from.
+func isDominatedCompletion(chosen []int, rest Items, residual Size, full, limited bool) bool {
+	excluded := make([]bool, len(rest))
+	for i := range excluded {
+		excluded[i] = true
+	}
+	for _, index := range chosen {
+		excluded[index] = false
+	}
+	// replaceable returns true when an excluded item y satisfies low <= y <= high
+	replaceable := func(low, high Size) bool {
+		for i, item := range rest {
+			if excluded[i] && Size(item) >= low && Size(item) <= high {
+				return true
+			}
+		}
+		return false
+	}
+
+	// an excluded item still fits, so the set is not maximal
+	if !full && replaceable(1, residual) {
+		return true
+	}
+	for i, first := range chosen {
+		firstSize := Size(rest[first])
+		if replaceable(firstSize+1, firstSize+residual) {
+			return true
+		}
+		if limited {
+			continue
+		}
+		for _, second := range chosen[i+1:] {
+			pairSize := firstSize + Size(rest[second])
+			if replaceable(pairSize, pairSize+residual) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package binpacking
+
+import (
+	"context"
+	"math"
+)
+
+// knapsackCellLimit the largest table the pricing knapsack may fill, as the
+// number of item chunks times the bin capacity plus one. Column generation
+// is not attempted on instances needing more.
+const knapsackCellLimit = 1 << 24
+
+// lpTolerance slack allowed when rounding the LP optimum up to a bin count
+const lpTolerance = 1e-6
+
+// lpPerturbation the most the demands in the master problem are increased by
+const lpPerturbation = 1e-6
+
+// lpRelaxation the LP relaxation of the Gilmore-Gomory cutting stock model
+// of an instance: choose how often to use each cutting pattern, a multiset
+// of item sizes fitting in one bin, so every item is covered, using the
+// fewest bins. Items are grouped by size, largest first.
+type lpRelaxation struct {
+	patterns [][]int   // number of items of each size in each pattern
+	usage    []float64 // how many times the LP uses each pattern
+	bound    float64   // a lower bound on the LP optimum, equal to it once solved
+}
+
+// groupBySize the items in compressed form, with
+// the positions of the items of each size
+func groupBySize(items Items, capacity Size) (*stockProblem, [][]int) {
+	problem := &stockProblem{capacity: capacity}
+	positions := make([][]int, 0)
+	for _, position := range items.decreasingOrder() {
+		size := Size(items[position])
+		if len(problem.sizes) == 0 || problem.sizes[len(problem.sizes)-1] != size {
+			problem.sizes = append(problem.sizes, size)
+			problem.demands = append(problem.demands, 0)
+			positions = append(positions, nil)
+		}
+		problem.demands[len(problem.demands)-1]++
+		positions[len(positions)-1] = append(positions[len(positions)-1], position)
+	}
+	return problem, positions
+}
+
+// solveRelaxation solve the LP relaxation by column generation. The
+// restricted master problem is solved by the simplex method, and the pattern
+// to add is the one of greatest value at the master's dual prices, found by
+// a bounded knapsack over the item sizes. The bound is Farley's: the
+// restricted optimum divided by the largest pattern value, which is the LP
+// optimum once no pattern is worth more than one, and is still a lower bound
+// if the context is done before then. Returns false if the knapsack table
+// would be too large.
+func solveRelaxation(ctx context.Context, problem *stockProblem) (*lpRelaxation, bool) {
+	sizes, demands, capacity := problem.sizes, problem.demands, problem.capacity
+	pricing, ok := newKnapsack(sizes, demands, capacity, problem.maxItems)
+	if !ok {
+		return nil, false
+	}
+	relaxation := &lpRelaxation{}
+	// each size has a surplus column, for items covered more than once
+	costs := make([]float64, len(sizes))
+	columns := make([][]float64, len(sizes))
+	bounds := make([]float64, len(sizes))
+	basis := make([]int, len(sizes))
+	for i, size := range sizes {
+		columns[i] = make([]float64, len(sizes))
+		columns[i][i] = -1
+		// perturbed so fewer pivots are degenerate, which the
+		// bound does not depend on, as it uses only the prices
+		bounds[i] = float64(demands[i]) + lpPerturbation*float64(i+1)/float64(len(sizes))
+		// start with each size on its own, as many times as fit
+		pattern := make([]int, len(sizes))
+		pattern[i] = problem.room(0, 0, size)
+		if pattern[i] > demands[i] {
+			pattern[i] = demands[i]
+		}
+		relaxation.patterns = append(relaxation.patterns, pattern)
+		basis[i] = len(sizes) + i
+	}
+	// and the patterns of the first fit decreasing packing
+	for _, group := range problem.fitDecreasing(demands, false) {
+		if !containsPattern(relaxation.patterns, group.pattern) {
+			relaxation.patterns = append(relaxation.patterns, group.pattern)
+		}
+	}
+	for _, pattern := range relaxation.patterns {
+		costs = append(costs, 1)
+		columns = append(columns, patternColumn(pattern))
+	}
+	lp, _ := newSimplex(costs, columns, bounds, basis) // the basis is diagonal and positive
+
+	for {
+		lp.solve() // bounded, since no column has a negative cost
+		relaxation.usage = lp.primal()[len(sizes):]
+		prices := lp.dual()
+		for i := range prices {
+			prices[i] = math.Max(prices[i], 0)
+		}
+		pattern, value := pricing.best(prices)
+		// any prices no pattern is worth more than one bin for bound the
+		// optimum, so rounding errors in the simplex cannot overstate it
+		var total float64
+		for i, price := range prices {
+			total += float64(demands[i]) * price
+		}
+		if farley := total / math.Max(value, 1); farley > relaxation.bound {
+			relaxation.bound = farley
+		}
+		if value <= 1+simplexEpsilon || ctx.Err() != nil || containsPattern(relaxation.patterns, pattern) {
+			return relaxation, true // solved, stopped, or stalled on rounding errors
+		}
+		relaxation.patterns = append(relaxation.patterns, pattern)
+		lp.addColumn(1, patternColumn(pattern))
+	}
+}
+
+// patternColumn the number of items of each size in a pattern, as a column of the master problem
+func patternColumn(pattern []int) []float64 {
+	column := make([]float64, len(pattern))
+	for i, count := range pattern {
+		column[i] = float64(count)
+	}
+	return column
+}
+
+// containsPattern whether a pattern is already in the list
+func containsPattern(patterns [][]int, pattern []int) bool {
+	for _, existing := range patterns {
+		same := true
+		for i := range existing {
+			same = same && existing[i] == pattern[i]
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// knapsack the bounded knapsack solved to price patterns. Up to its demand,
+// any number of items of a size is a sum of chunks of 1, 2, 4, ... items,
+// so it is solved as a 0-1 knapsack over the chunks. When bins hold at most
+// maxItems items, the table also tracks the number of items taken.
+type knapsack struct {
+	sizes    []Size
+	capacity Size
+	maxItems int
+	chunks   []knapsackChunk
+}
+
+// knapsackChunk some number of items of one size, taken together
+type knapsackChunk struct {
+	size, count int
+}
+
+// newKnapsack split the demands into chunks, returning false if
+// the table would exceed knapsackCellLimit
+func newKnapsack(sizes []Size, demands []int, capacity Size, maxItems int) (*knapsack, bool) {
+	problem := &knapsack{sizes: sizes, capacity: capacity, maxItems: maxItems}
+	for i, demand := range demands {
+		for count := 1; demand > 0; count *= 2 {
+			if count > demand {
+				count = demand
+			}
+			problem.chunks = append(problem.chunks, knapsackChunk{i, count})
+			demand -= count
+		}
+	}
+	return problem, float64(len(problem.chunks))*float64(capacity+1)*float64(problem.layers()) <= knapsackCellLimit
+}
+
+// layers the number of item counts the table tracks for each capacity
+func (problem *knapsack) layers() int {
+	if problem.maxItems > 0 {
+		return problem.maxItems + 1
+	}
+	return 1
+}
+
+// best the pattern of greatest total value given the value of one item
+// of each size, with that value
+func (problem *knapsack) best(values []float64) ([]int, float64) {
+	capacity, layers := int(problem.capacity), problem.layers()
+	// best value using at most c of the capacity and, when the number of
+	// items is limited, at most n items, at c*layers+n
+	best := make([]float64, (capacity+1)*layers)
+	taken := make([][]bool, len(problem.chunks))
+	for k, chunk := range problem.chunks {
+		taken[k] = make([]bool, (capacity+1)*layers)
+		weight := int(problem.sizes[chunk.size]) * chunk.count
+		value := values[chunk.size] * float64(chunk.count)
+		items := chunk.count
+		if layers == 1 {
+			items = 0 // not counted
+		}
+		if value <= 0 {
+			continue
+		}
+		for c := capacity; c >= weight; c-- {
+			for n := layers - 1; n >= items; n-- {
+				cell, from := c*layers+n, (c-weight)*layers+n-items
+				if best[from]+value > best[cell] {
+					best[cell] = best[from] + value
+					taken[k][cell] = true
+				}
+			}
+		}
+	}
+	pattern := make([]int, len(problem.sizes))
+	for k, c, n := len(problem.chunks)-1, capacity, layers-1; k >= 0; k-- {
+		if taken[k][c*layers+n] {
+			chunk := problem.chunks[k]
+			pattern[chunk.size] += chunk.count
+			c -= int(problem.sizes[chunk.size]) * chunk.count
+			if layers > 1 {
+				n -= chunk.count
+			}
+		}
+	}
+	return pattern, best[capacity*layers+layers-1]
+}
+
+// LowerBoundLP the LP relaxation of the Gilmore-Gomory cutting stock model,
+// solved by column generation and rounded up. Bin packing instances nearly
+// always need exactly this many bins (the integer round-up property), so it
+// is the tightest of the bounds, but also the slowest: a few hundred
+// distinct item sizes can take a minute. Its pricing table grows with the bin
+// size times the number of distinct item sizes; when that would be too
+// large, LowerBoundDFF is used instead.
+func LowerBoundLP(items Items, binSize Size) Count {
+	problem, _ := groupBySize(items, binSize)
+	relaxation, ok := solveRelaxation(context.Background(), problem)
+	if !ok {
+		return LowerBoundDFF(items, binSize)
+	}
+	return relaxation.lowerBound()
+}
+
+// lowerBound the bound rounded up to a whole number of bins
+func (relaxation *lpRelaxation) lowerBound() Count {
+	return Count(math.Ceil(relaxation.bound - lpTolerance))
+}
+
+// roundRelaxation the packing using each pattern as many whole times as
+// the LP uses it, leaving out items already packed, with the items left
+// over packed by first fit decreasing
+func (problem *stockProblem) roundRelaxation(relaxation *lpRelaxation) []*stockGroup {
+	remaining := make([]int, len(problem.demands))
+	copy(remaining, problem.demands)
+	groups := make([]*stockGroup, 0)
+	for p, usage := range relaxation.usage {
+		for copies := int(math.Floor(usage + lpTolerance)); copies > 0; {
+			// as many copies as there are items for, then one with fewer items
+			group := &stockGroup{pattern: make([]int, len(remaining)), count: copies}
+			for i, count := range relaxation.patterns[p] {
+				if count > 0 && remaining[i]/count < group.count {
+					group.count = remaining[i] / count
+				}
+			}
+			if group.count == 0 {
+				group.count = 1
+			}
+			for i, count := range relaxation.patterns[p] {
+				if count*group.count > remaining[i] {
+					count = remaining[i]
+				}
+				group.pattern[i] = count
+				group.load += Size(count) * problem.sizes[i]
+				remaining[i] -= count * group.count
+			}
+			if group.load == 0 {
+				break
+			}
+			groups = append(groups, group)
+			copies -= group.count
+		}
+	}
+	return append(groups, problem.fitDecreasing(remaining, false)...)
+}
+
+// PackAllColumnGeneration pack all items by solving the LP relaxation of
+// the cutting stock model with column generation, then rounding it: each
+// pattern is used as many whole times as the LP uses it, and the items left
+// over are packed with first fit decreasing. If that does not meet the
+// rounded up LP bound, the MTP branch-and-bound search is run from it with
+// that bound, so the result is optimal when the search finishes. When the
+// instance is too large for column generation, MTP is run on its own.
+// Both stages stop when the time limit or context is done. When MaxItems is
+// set, patterns hold at most that many items.
+func (binCollection *BinCollectionImpl) PackAllColumnGeneration(ctx context.Context, items Items) {
+	ctx, cancel := binCollection.withTimeLimit(ctx)
+	defer cancel()
+	problem, positions := groupBySize(items, binCollection.BinCapacity)
+	problem.maxItems = int(binCollection.MaxItems)
+	relaxation, ok := solveRelaxation(ctx, problem)
+	if !ok {
+		binCollection.PackAllMTP(ctx, items)
+		return
+	}
+
+	assigned := make([]int, len(items))
+	bins := 0
+	for _, group := range problem.roundRelaxation(relaxation) {
+		for ; group.count > 0; group.count-- {
+			for i, count := range group.pattern {
+				for _, position := range positions[i][:count] {
+					assigned[position] = bins
+				}
+				positions[i] = positions[i][count:]
+			}
+			bins++
+		}
+	}
+
+	floor := relaxation.lowerBound()
+	if countBins(assigned) <= floor {
+		binCollection.setAssignment(items, assigned)
+		binCollection.Status = Optimal
+		return
+	}
+	binCollection.solveMTP(ctx, items, assigned, floor)
+}
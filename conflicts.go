@@ -0,0 +1,260 @@
+package binpacking
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+)
+
+// Conflict a pair of items, by their positions in the input, which may not share a bin
+type Conflict [2]int
+
+// Conflicts collection type for Conflict
+type Conflicts []Conflict
+
+// validate check that every conflict names two different items of the input
+func (conflicts Conflicts) validate(itemCount int) error {
+	for i, conflict := range conflicts {
+		first, second := conflict[0], conflict[1]
+		if first < 0 || first >= itemCount || second < 0 || second >= itemCount || first == second {
+			return &InvalidConflictError{Index: i, Conflict: conflict}
+		}
+	}
+	return nil
+}
+
+// graph the positions of the items in conflict with each of the given number of items
+func (conflicts Conflicts) graph(itemCount int) [][]int {
+	graph := make([][]int, itemCount)
+	for _, conflict := range conflicts {
+		graph[conflict[0]] = append(graph[conflict[0]], conflict[1])
+		graph[conflict[1]] = append(graph[conflict[1]], conflict[0])
+	}
+	return graph
+}
+
+// clique the size of a set of items which are all in conflict with one
+// another, found greedily, and so a lower bound on the number of bins
+func clique(graph [][]int) int {
+	largest := 0
+	if len(graph) > 0 {
+		largest = 1
+	}
+	adjacent := make(map[Conflict]bool)
+	for item, neighbours := range graph {
+		for _, other := range neighbours {
+			adjacent[Conflict{item, other}] = true
+		}
+	}
+	for item, neighbours := range graph {
+		if len(neighbours) < largest {
+			continue // cannot start a larger clique
+		}
+		// grow the clique from the neighbours with the most conflicts
+		candidates := append([]int(nil), neighbours...)
+		sort.Slice(candidates, func(a, b int) bool {
+			return len(graph[candidates[a]]) > len(graph[candidates[b]])
+		})
+		members := []int{item}
+		for _, candidate := range candidates {
+			joins := true
+			for _, member := range members {
+				joins = joins && adjacent[Conflict{candidate, member}]
+			}
+			if joins {
+				members = append(members, candidate)
+			}
+		}
+		if len(members) > largest {
+			largest = len(members)
+		}
+	}
+	return largest
+}
+
+// conflictPackers the algorithms which can keep conflicting items apart
+var conflictPackers = map[Algorithm]Packer{
+	FirstFit:           conflictFitPacker{},
+	FirstFitDecreasing: conflictFitPacker{decreasing: true},
+	BestFit:            conflictFitPacker{best: true},
+	BestFitDecreasing:  conflictFitPacker{best: true, decreasing: true},
+	PackingConstraint: PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+		return binCollection.PackAllConstraint(ctx, items)
+	}),
+	DSatur: PackerFunc(func(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+		return binCollection.PackAllDSatur(ctx, items)
+	}),
+}
+
+// ConflictAlgorithms get the names of the algorithms which can keep conflicting items apart
+func ConflictAlgorithms() []string {
+	algorithms := make([]Algorithm, 0, len(conflictPackers))
+	for algorithm := range conflictPackers {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool { return algorithms[i] < algorithms[j] })
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = algorithm.String()
+	}
+	return names
+}
+
+// conflictTracker the bin of each packed item, so an item is
+// kept out of the bins holding those it is in conflict with
+type conflictTracker struct {
+	graph [][]int
+	binOf []int // bin of each item, -1 until it is packed
+}
+
+// newConflictTracker track the items of the collection's conflicts
+func newConflictTracker(conflicts Conflicts, itemCount int) *conflictTracker {
+	tracker := &conflictTracker{graph: conflicts.graph(itemCount), binOf: make([]int, itemCount)}
+	for i := range tracker.binOf {
+		tracker.binOf[i] = -1
+	}
+	return tracker
+}
+
+// blocked the bins holding an item in conflict with the item at the given position
+func (tracker *conflictTracker) blocked(position int) map[int]bool {
+	blocked := make(map[int]bool)
+	for _, other := range tracker.graph[position] {
+		if tracker.binOf[other] >= 0 {
+			blocked[tracker.binOf[other]] = true
+		}
+	}
+	return blocked
+}
+
+// pack pack the item at the given position into the first bin it fits
+// and is not blocked from, or the tightest such bin if best is set,
+// opening a new bin if there is none, and returning the bin's index
+func (tracker *conflictTracker) pack(binCollection *BinCollectionImpl, item Item, position int, best bool) int {
+	blocked := tracker.blocked(position)
+	index := -1
+	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
+		bin := binCollection.binAt(i)
+		if blocked[i] || !bin.CanFit(item) {
+			continue
+		}
+		if !best {
+			index = i
+			break
+		}
+		if index < 0 || bin.Remaining() < binCollection.binAt(index).Remaining() {
+			index = i
+		}
+	}
+	if index < 0 {
+		binCollection.NewBin()
+		index = int(binCollection.GetTotalBins()) - 1
+	}
+	binCollection.binAt(index).PackIndex(item, position)
+	tracker.binOf[position] = index
+	return index
+}
+
+// conflictFitPacker packs all items using first fit, or best fit, skipping
+// the bins which hold an item in conflict with the one being packed
+type conflictFitPacker struct {
+	best       bool
+	decreasing bool
+}
+
+// PackAll pack every item in succession
+func (packer conflictFitPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	order := items.inputOrder()
+	if packer.decreasing {
+		order = items.decreasingOrder()
+	}
+	tracker := newConflictTracker(binCollection.Conflicts, len(items))
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tracker.pack(binCollection, items[position], position, packer.best)
+	}
+	return nil
+}
+
+// PackAllDSatur pack all items in the manner of Brélaz's DSatur graph
+// coloring, treating bins as colors: the next item packed is the one kept
+// out of the most bins by the items it is in conflict with, then the
+// largest, then the one with the most conflicts, and it goes into the
+// tightest bin it fits and is not kept out of. Without conflicts this
+// is best fit decreasing.
+func (binCollection *BinCollectionImpl) PackAllDSatur(ctx context.Context, items Items) error {
+	tracker := newConflictTracker(binCollection.Conflicts, len(items))
+	queue := &saturationQueue{items: items, graph: tracker.graph}
+	saturation := make([]map[int]bool, len(items)) // bins each item is kept out of
+	for position := range items {
+		heap.Push(queue, saturationEntry{position: position})
+	}
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entry := heap.Pop(queue).(saturationEntry)
+		position := entry.position
+		if tracker.binOf[position] >= 0 || entry.saturation != len(saturation[position]) {
+			continue // packed already, or kept out of more bins since queued
+		}
+		index := tracker.pack(binCollection, items[position], position, true)
+		for _, other := range tracker.graph[position] {
+			if tracker.binOf[other] < 0 && !saturation[other][index] {
+				if saturation[other] == nil {
+					saturation[other] = make(map[int]bool)
+				}
+				saturation[other][index] = true
+				heap.Push(queue, saturationEntry{position: other, saturation: len(saturation[other])})
+			}
+		}
+	}
+	return nil
+}
+
+// saturationEntry an item waiting to be packed by DSatur, with the
+// number of bins it was kept out of when queued
+type saturationEntry struct {
+	position   int
+	saturation int
+}
+
+// saturationQueue a heap of the items waiting to be packed by DSatur,
+// the next to pack first
+type saturationQueue struct {
+	items   Items
+	graph   [][]int
+	entries []saturationEntry
+}
+
+func (queue *saturationQueue) Len() int { return len(queue.entries) }
+
+func (queue *saturationQueue) Less(i, j int) bool {
+	first, second := queue.entries[i], queue.entries[j]
+	if first.saturation != second.saturation {
+		return first.saturation > second.saturation
+	}
+	if queue.items[first.position] != queue.items[second.position] {
+		return queue.items[first.position] > queue.items[second.position]
+	}
+	if len(queue.graph[first.position]) != len(queue.graph[second.position]) {
+		return len(queue.graph[first.position]) > len(queue.graph[second.position])
+	}
+	return first.position < second.position
+}
+
+func (queue *saturationQueue) Swap(i, j int) {
+	queue.entries[i], queue.entries[j] = queue.entries[j], queue.entries[i]
+}
+
+func (queue *saturationQueue) Push(x interface{}) {
+	queue.entries = append(queue.entries, x.(saturationEntry))
+}
+
+func (queue *saturationQueue) Pop() interface{} {
+	last := queue.entries[len(queue.entries)-1]
+	queue.entries = queue.entries[:len(queue.entries)-1]
+	return last
+}
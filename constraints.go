@@ -1,31 +1,65 @@
 package binpacking
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/gnboorse/centipede"
 )
 
-// PackAllConstraint pack all items using constraints
-func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
+// PackAllConstraint pack all items using constraints, placing the largest
+// first. Items in any of the collection's Conflicts are kept in different bins,
+// and no bin holds more than MaxItems items when that is set.
+// Returns ErrInfeasible if the solver cannot place every item. If the
+// context is done before the search finishes, the first fit decreasing
+// packing is used instead and the status is set to NotProvenOptimal.
+func (binCollection *BinCollectionImpl) PackAllConstraint(ctx context.Context, items Items) error {
+	if err := binCollection.Conflicts.validate(len(items)); err != nil {
+		return err
+	}
+	// init bins for packing constraint
+	if binCollection.GetTotalBins() == 0 {
+		lowerBound := CalculateLowerBound(items, binCollection.BinCapacity)
+		if binCollection.MaxItems > 0 {
+			if cardinality := LowerBoundCardinality(items, binCollection.BinCapacity, binCollection.MaxItems); cardinality > lowerBound {
+				lowerBound = cardinality
+			}
+		}
+		roundedLowerBound := int(math.Round(float64(lowerBound) * 1.2))
+		// conflicting items need at least as many bins as there are items all in conflict
+		if conflicting := clique(binCollection.Conflicts.graph(len(items))); conflicting > roundedLowerBound {
+			roundedLowerBound = conflicting
+		}
+		for i := 0; i < roundedLowerBound; i++ {
+			binCollection.NewBin() // add new bins for all of them
+		}
+	}
 	itemCount := len(items)
 	vars := make(centipede.Variables, 0)
 	constraints := make(centipede.Constraints, 0)
 	propagations := make(centipede.Propagations, 0)
 
-	// placement range can be any index in the range of bins
-	itemPlacementVariableNames := make(centipede.VariableNames, 0)
+	// placement range can be any index in the range of bins,
+	// with the variables of the largest items assigned first
+	itemPlacementVariableNames := make(centipede.VariableNames, itemCount)
 	itemPlacementVariableDomain := centipede.IntRange(0, int(binCollection.GetTotalBins()))
-	for i := 0; i < itemCount; i++ {
+	for _, i := range items.decreasingOrder() {
 		itemPlacementVariableName := centipede.VariableName("ItemPlacement" + strconv.Itoa(i))
 		vars = append(vars, centipede.NewVariable(itemPlacementVariableName, itemPlacementVariableDomain))
-		itemPlacementVariableNames = append(itemPlacementVariableNames, itemPlacementVariableName)
+		itemPlacementVariableNames[i] = itemPlacementVariableName
 	}
 
 	sumConstraint := centipede.Constraint{
 		Vars: itemPlacementVariableNames,
 		ConstraintFunction: func(variables *centipede.Variables) bool {
+			// failing every check once the context is done unwinds the search quickly
+			if ctx.Err() != nil {
+				return false
+			}
 			sums := make([]int, binCollection.GetTotalBins(), binCollection.GetTotalBins())
+			counts := make([]Count, binCollection.GetTotalBins())
 			for i := 0; i < itemCount; i++ {
 				itemPositionVar := variables.Find(itemPlacementVariableNames[i])
 				if !itemPositionVar.Empty {
@@ -35,6 +69,10 @@ func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
 						return false
 					}
 					sums[itemPosition] += itemSize
+					counts[itemPosition]++
+					if binCollection.MaxItems > 0 && counts[itemPosition] > binCollection.MaxItems {
+						return false
+					}
 				}
 			}
 			return true
@@ -48,8 +86,10 @@ func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
 		Vars: itemPlacementVariableNames,
 		PropagationFunction: func(assignment centipede.VariableAssignment, variables *centipede.Variables) []centipede.DomainRemoval {
 			binIndexAssigned := assignment.Value.(int)
-			// calculate runningSum to be the total sum of all items placed in the bin just assigned to
+			// calculate runningSum to be the total sum of all items placed in the bin just assigned to,
+			// and runningCount their number
 			runningSum := 0
+			var runningCount Count
 			potentialDomainRemovals := make(centipede.DomainRemovals, 0)
 			// iterate over items
 			for i := 0; i < itemCount; i++ {
@@ -61,6 +101,7 @@ func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
 					if itemPosition == binIndexAssigned {
 						itemSize := int(items[i])
 						runningSum += itemSize
+						runningCount++
 					}
 				} else {
 					// pre-calculate what our domain removals would be if we have maxed out the sum
@@ -69,8 +110,10 @@ func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
 						Value:        assignment.Value})
 				}
 			}
-			// return domain removals if necessary
-			if runningSum > int(binCollection.BinCapacity) {
+			// return domain removals if necessary, including once the bin holds
+			// as many items as it may, since it can take no more
+			if runningSum > int(binCollection.BinCapacity) ||
+				(binCollection.MaxItems > 0 && runningCount >= binCollection.MaxItems) {
 				return potentialDomainRemovals
 			}
 			return []centipede.DomainRemoval{}
@@ -79,18 +122,71 @@ func (binCollection *BinCollectionImpl) PackAllConstraint(items Items) {
 
 	propagations = append(propagations, sumPropagation)
 
+	// conflicting items may not be placed in the same bin, so placing
+	// one removes its bin from the other's domain
+	for _, conflict := range binCollection.Conflicts {
+		conflictVariableNames := centipede.VariableNames{
+			itemPlacementVariableNames[conflict[0]],
+			itemPlacementVariableNames[conflict[1]]}
+		constraints = append(constraints, centipede.Constraint{
+			Vars: conflictVariableNames,
+			ConstraintFunction: func(variables *centipede.Variables) bool {
+				first := variables.Find(conflictVariableNames[0])
+				second := variables.Find(conflictVariableNames[1])
+				return first.Empty || second.Empty || first.Value != second.Value
+			},
+		})
+		propagations = append(propagations, centipede.Propagation{
+			Vars: conflictVariableNames,
+			PropagationFunction: func(assignment centipede.VariableAssignment, variables *centipede.Variables) []centipede.DomainRemoval {
+				other := conflictVariableNames[0]
+				if assignment.VariableName == other {
+					other = conflictVariableNames[1]
+				}
+				if !variables.Find(other).Empty {
+					return []centipede.DomainRemoval{}
+				}
+				return []centipede.DomainRemoval{{VariableName: other, Value: assignment.Value}}
+			},
+		})
+	}
+
 	// create solver
 	solver := centipede.NewBackTrackingCSPSolverWithPropagation(vars, constraints, propagations)
 
 	// solve for constraints
 	solver.Solve()
+	if ctx.Err() != nil {
+		return binCollection.packIncumbent(items)
+	}
+
+	// make sure every item was assigned before packing any of them
+	for i := 0; i < itemCount; i++ {
+		if solver.State.Vars.Find(itemPlacementVariableNames[i]).Empty {
+			return fmt.Errorf("%w within %v bins", ErrInfeasible, binCollection.GetTotalBins())
+		}
+	}
 
 	for i := 0; i < itemCount; i++ {
 		itemPlacementVariableName := itemPlacementVariableNames[i]
 		variableValue := solver.State.Vars.Find(itemPlacementVariableName)
-		if !variableValue.Empty {
-			bin := binCollection.GetBin(variableValue.Value.(int))
-			bin.Pack(items[i])
-		}
+		bin := binCollection.binAt(variableValue.Value.(int))
+		bin.PackIndex(items[i], i)
+	}
+	return nil
+}
+
+// packIncumbent replace the bins set up for the search with a first fit
+// decreasing packing, which keeps conflicting items apart, for when the
+// search is stopped before it finishes
+func (binCollection *BinCollectionImpl) packIncumbent(items Items) error {
+	binCollection.Bins = make(Bins, 0)
+	binCollection.TotalBins = 0
+	binCollection.Status = NotProvenOptimal
+	var packer Packer = firstFitPacker{decreasing: true}
+	if len(binCollection.Conflicts) > 0 {
+		packer = conflictFitPacker{decreasing: true}
 	}
+	// the context is already done, which would stop the packer too
+	return packer.PackAll(context.Background(), binCollection, items)
 }
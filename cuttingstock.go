@@ -0,0 +1,341 @@
+package binpacking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Demand a number of items of the same size, as ordered in a cutting stock problem
+type Demand struct {
+	Size     Size  `json:"size"`
+	Quantity Count `json:"quantity"`
+}
+
+// Demands collection type for Demand
+type Demands []Demand
+
+// CuttingStockList input for a cutting stock problem: a bin packing
+// problem with the items given as sizes and the quantity of each,
+// rather than one by one
+type CuttingStockList struct {
+	// BinSize the size of the bins being packed
+	Size `json:"capacity"`
+	// Algorithm the algorithm being used to solve the problem
+	Algorithm Algorithm `json:"algorithm"`
+	// Demands the items being passed in
+	Demands    `json:"demands"`
+	LowerBound Count `json:"lowerBound"`
+	// MaxItems the most items any bin may hold, unlimited when zero
+	MaxItems Count `json:"maxItems,omitempty"`
+	// Options parameters passed on to the algorithm
+	Options
+}
+
+// Pattern a way of filling a bin, and how many bins are filled that way
+type Pattern struct {
+	// Items the size of each kind of item in the bin, and how many it holds
+	Items    Demands `json:"items"`
+	Usage    Size    `json:"usage"`
+	Quantity Count   `json:"quantity"`
+}
+
+// CuttingStock an instance of the cutting stock problem. Its solution
+// is the distinct patterns of the bins rather than the bins themselves,
+// so it is no larger for a million items than for a hundred.
+type CuttingStock struct {
+	BinCapacity  Size      `json:"capacity"`
+	TotalBins    Count     `json:"count"`
+	Patterns     []Pattern `json:"patterns"`
+	Algorithm    Algorithm `json:"algorithm"`
+	Status       Status    `json:"status"`
+	SolutionTime int64     `json:"solution_time"`
+	// Nodes the number of search tree nodes explored by an exact algorithm
+	Nodes int64 `json:"nodes,omitempty"`
+	// MaxItems the most items any bin may hold, unlimited when zero
+	MaxItems Count `json:"maxItems,omitempty"`
+	Options
+}
+
+// cuttingStockSolvers the algorithms which can solve the cutting
+// stock problem in its compressed form, without listing every item
+var cuttingStockSolvers = map[Algorithm]func(*CuttingStock, context.Context, *stockProblem){
+	FirstFitDecreasing: func(cuttingStock *CuttingStock, ctx context.Context, problem *stockProblem) {
+		cuttingStock.setGroups(problem, problem.fitDecreasing(problem.demands, false))
+	},
+	BestFitDecreasing: func(cuttingStock *CuttingStock, ctx context.Context, problem *stockProblem) {
+		cuttingStock.setGroups(problem, problem.fitDecreasing(problem.demands, true))
+	},
+	MartelloToth:     (*CuttingStock).packBranchAndBound,
+	ColumnGeneration: (*CuttingStock).packColumnGeneration,
+}
+
+// CuttingStockAlgorithms get the names of the algorithms which can solve cutting stock problems
+func CuttingStockAlgorithms() []string {
+	algorithms := make([]Algorithm, 0, len(cuttingStockSolvers))
+	for algorithm := range cuttingStockSolvers {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool { return algorithms[i] < algorithms[j] })
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = algorithm.String()
+	}
+	return names
+}
+
+// NewCuttingStock create an instance of the cutting stock problem
+// from a CuttingStockList object
+func NewCuttingStock(list *CuttingStockList) *CuttingStock {
+	return &CuttingStock{
+		BinCapacity: list.Size,
+		Patterns:    make([]Pattern, 0),
+		Algorithm:   list.Algorithm,
+		MaxItems:    list.MaxItems,
+		Options:     list.Options}
+}
+
+// PackAll solve the cutting stock problem for the given demands
+func (cuttingStock *CuttingStock) PackAll(demands Demands) error {
+	return cuttingStock.PackAllContext(context.Background(), demands)
+}
+
+// PackAllContext solve the cutting stock problem for the given demands,
+// giving up when the context is done, as PackAllContext does for bin
+// packing. Demands of the same size are combined. Supported algorithms are
+// FirstFitDecreasing and BestFitDecreasing, which fill whole runs of
+// identical bins at once, and the exact MartelloToth and ColumnGeneration,
+// which search over patterns rather than items. No pattern holds more than
+// MaxItems items when that is set.
+func (cuttingStock *CuttingStock) PackAllContext(ctx context.Context, demands Demands) error {
+	solve, ok := cuttingStockSolvers[cuttingStock.Algorithm]
+	if !ok {
+		return &UnknownAlgorithmError{Algorithm: cuttingStock.Algorithm}
+	}
+	if cuttingStock.BinCapacity <= 0 {
+		return &InvalidCapacityError{Capacity: cuttingStock.BinCapacity}
+	}
+	if cuttingStock.MaxItems < 0 {
+		return &InvalidMaxItemsError{MaxItems: cuttingStock.MaxItems}
+	}
+	quantities := make(map[Size]int)
+	for i, demand := range demands {
+		if err := validateItem(i, Item(demand.Size), cuttingStock.BinCapacity); err != nil {
+			return err
+		}
+		if demand.Quantity < 0 {
+			return &InvalidQuantityError{Index: i, Quantity: demand.Quantity}
+		}
+		quantities[demand.Size] += int(demand.Quantity)
+	}
+	problem := &stockProblem{capacity: cuttingStock.BinCapacity, maxItems: int(cuttingStock.MaxItems)}
+	for size, quantity := range quantities {
+		if quantity > 0 {
+			problem.sizes = append(problem.sizes, size)
+		}
+	}
+	sort.Slice(problem.sizes, func(i, j int) bool { return problem.sizes[i] > problem.sizes[j] })
+	for _, size := range problem.sizes {
+		problem.demands = append(problem.demands, quantities[size])
+	}
+
+	cuttingStock.Status = Unsolved
+	cuttingStock.Nodes = 0
+	ctx, cancel := cuttingStock.withTimeLimit(ctx)
+	defer cancel()
+	solve(cuttingStock, ctx, problem)
+	if cuttingStock.Status == Unsolved {
+		cuttingStock.Status = Feasible
+	}
+	return nil
+}
+
+// GetTotalBins getter for the total number of bins
+func (cuttingStock *CuttingStock) GetTotalBins() Count {
+	return cuttingStock.TotalBins
+}
+
+// String get the JSON representation of the solution
+func (cuttingStock *CuttingStock) String() string {
+	jsonString, _ := json.MarshalIndent(cuttingStock, "", "  ")
+	return string(jsonString)
+}
+
+// SetTime set the execution time for a single run
+func (cuttingStock *CuttingStock) SetTime(nanoseconds int64) {
+	cuttingStock.SolutionTime = nanoseconds
+}
+
+// setGroups replace the solution's patterns with the given groups of
+// bins, combining groups with the same pattern
+func (cuttingStock *CuttingStock) setGroups(problem *stockProblem, groups []*stockGroup) {
+	cuttingStock.Patterns = make([]Pattern, 0)
+	cuttingStock.TotalBins = 0
+	for _, group := range mergeGroups(groups) {
+		pattern := Pattern{Items: make(Demands, 0), Usage: group.load, Quantity: Count(group.count)}
+		for i, count := range group.pattern {
+			if count > 0 {
+				pattern.Items = append(pattern.Items, Demand{Size: problem.sizes[i], Quantity: Count(count)})
+			}
+		}
+		cuttingStock.Patterns = append(cuttingStock.Patterns, pattern)
+		cuttingStock.TotalBins += Count(group.count)
+	}
+}
+
+// stockProblem a problem in compressed form: the distinct item
+// sizes, largest first, and the number of items of each size
+type stockProblem struct {
+	capacity Size
+	sizes    []Size
+	demands  []int
+	maxItems int // most items in a bin, unlimited when zero
+}
+
+// room how many more items of the given size fit in a bin
+// of the given load which holds the given number of items
+func (problem *stockProblem) room(load Size, count int, size Size) int {
+	fit := int((problem.capacity - load) / size)
+	if problem.maxItems > 0 && fit > problem.maxItems-count {
+		fit = problem.maxItems - count
+	}
+	return fit
+}
+
+// stockGroup a number of identical bins, each holding
+// the given number of items of each size
+type stockGroup struct {
+	pattern []int
+	load    Size
+	count   int
+}
+
+// items the number of items in each of the group's bins
+func (group *stockGroup) items() int {
+	items := 0
+	for _, count := range group.pattern {
+		items += count
+	}
+	return items
+}
+
+// fitDecreasing pack the items with first fit decreasing, or best fit
+// decreasing if best is set, a whole size at a time. Each bin a size fits in
+// takes as many items of that size as fit, and identical bins take items the
+// same way, so each group of identical bins splits into at most three: those
+// filled, one partly filled, and those not reached. Returns the groups in
+// the order the bins were opened, so the bins are as first fit would pack
+// the items one by one.
+func (problem *stockProblem) fitDecreasing(demands []int, best bool) []*stockGroup {
+	groups := make([]*stockGroup, 0)
+	for i, size := range problem.sizes {
+		remaining := demands[i]
+		visit := make([]*stockGroup, len(groups))
+		copy(visit, groups)
+		if best {
+			sort.SliceStable(visit, func(a, b int) bool { return visit[a].load > visit[b].load })
+		}
+		for _, group := range visit {
+			if remaining == 0 {
+				break
+			}
+			fit := problem.room(group.load, group.items(), size)
+			if fit <= 0 {
+				continue
+			}
+			filled := remaining / fit
+			if filled > group.count {
+				filled = group.count
+			}
+			partial := 0
+			if filled < group.count {
+				partial = remaining - filled*fit
+			}
+			remaining -= filled*fit + partial
+			rest := group.count - filled
+			pieces := []*stockGroup{group.with(i, size, fit, filled)}
+			if partial > 0 {
+				pieces = append(pieces, group.with(i, size, partial, 1))
+				rest--
+			}
+			pieces = append(pieces, group.with(i, size, 0, rest))
+			groups = replaceGroup(groups, group, pieces)
+		}
+		if remaining > 0 {
+			empty := &stockGroup{pattern: make([]int, len(problem.sizes))}
+			fit := problem.room(0, 0, size)
+			groups = append(groups, empty.with(i, size, fit, remaining/fit), empty.with(i, size, remaining%fit, 1))
+		}
+		groups = withoutEmptyGroups(groups)
+	}
+	return groups
+}
+
+// with a copy of the group holding some more items of one size, with the given number of bins
+func (group *stockGroup) with(index int, size Size, items, count int) *stockGroup {
+	pattern := make([]int, len(group.pattern))
+	copy(pattern, group.pattern)
+	pattern[index] += items
+	return &stockGroup{pattern: pattern, load: group.load + Size(items)*size, count: count}
+}
+
+// replaceGroup put the pieces of a group in its place
+func replaceGroup(groups []*stockGroup, group *stockGroup, pieces []*stockGroup) []*stockGroup {
+	for i, existing := range groups {
+		if existing == group {
+			replaced := make([]*stockGroup, 0, len(groups)+len(pieces))
+			replaced = append(replaced, groups[:i]...)
+			replaced = append(replaced, pieces...)
+			return append(replaced, groups[i+1:]...)
+		}
+	}
+	return groups
+}
+
+// withoutEmptyGroups drop groups with no bins or no items
+func withoutEmptyGroups(groups []*stockGroup) []*stockGroup {
+	kept := groups[:0]
+	for _, group := range groups {
+		if group.count > 0 && group.load > 0 {
+			kept = append(kept, group)
+		}
+	}
+	return kept
+}
+
+// mergeGroups combine groups with the same pattern, keeping the order
+// in which each pattern first appears
+func mergeGroups(groups []*stockGroup) []*stockGroup {
+	merged := make([]*stockGroup, 0, len(groups))
+	seen := make(map[string]*stockGroup)
+	for _, group := range groups {
+		key := fmt.Sprint(group.pattern)
+		if existing, ok := seen[key]; ok {
+			existing.count += group.count
+			continue
+		}
+		copied := *group
+		seen[key] = &copied
+		merged = append(merged, &copied)
+	}
+	return merged
+}
+
+// groupCount the number of bins in the groups
+func groupCount(groups []*stockGroup) Count {
+	bins := 0
+	for _, group := range groups {
+		bins += group.count
+	}
+	return Count(bins)
+}
+
+// heuristicGroups the better packing of first fit decreasing and best fit decreasing
+func (problem *stockProblem) heuristicGroups() []*stockGroup {
+	first := problem.fitDecreasing(problem.demands, false)
+	if best := problem.fitDecreasing(problem.demands, true); groupCount(best) < groupCount(first) {
+		return best
+	}
+	return first
+}
@@ -1,5 +1,7 @@
 package binpacking
 
+import "sort"
+
 // Item representation of an item being packed into a bin
 type Item int
 
@@ -20,8 +22,58 @@ func (items Items) Swap(i, j int) {
 	items[i], items[j] = items[j], items[i]
 }
 
+// decreasingOrder positions of the items ordered by size (decreasing),
+// with equal items kept in their original order
+func (items Items) decreasingOrder() []int {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		first, second := order[a], order[b]
+		return items[first] > items[second] || (items[first] == items[second] && first < second)
+	})
+	return order
+}
+
+// inputOrder positions of the items in their original order
+func (items Items) inputOrder() []int {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// SortedDecreasing get a copy of the items sorted by size (decreasing),
+// leaving the original slice untouched
+func (items Items) SortedDecreasing() Items {
+	sorted := make(Items, len(items))
+	copy(sorted, items)
+	sort.Sort(sort.Reverse(sorted))
+	return sorted
+}
+
 // Size scalar indicating the size of something
 type Size int
 
+// Sizer anything with a size that can be packed into a bin
+type Sizer interface {
+	Size() Size
+}
+
+// LabeledItem an item carrying an identifier and an optional payload,
+// for callers who need to know where each of their objects was packed
+type LabeledItem struct {
+	ID      string      `json:"id"`
+	Item    Item        `json:"size"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// Size the size of the labeled item
+func (item LabeledItem) Size() Size {
+	return Size(item.Item)
+}
+
 // Count scalar indicating a number of items
 type Count int
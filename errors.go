@@ -0,0 +1,186 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInfeasible returned when a solver could not find any assignment
+// of items to bins
+var ErrInfeasible = errors.New("no feasible packing found")
+
+// UnknownAlgorithmError returned when no Packer is registered for an algorithm
+type UnknownAlgorithmError struct {
+	Algorithm Algorithm
+}
+
+func (err *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unsupported algorithm: %v", err.Algorithm)
+}
+
+// InvalidCapacityError returned when bins have a non-positive capacity
+type InvalidCapacityError struct {
+	Capacity Size
+}
+
+func (err *InvalidCapacityError) Error() string {
+	return fmt.Sprintf("invalid bin capacity: %v", err.Capacity)
+}
+
+// InvalidMaxItemsError returned when bins may hold a negative number of items
+type InvalidMaxItemsError struct {
+	MaxItems Count
+}
+
+func (err *InvalidMaxItemsError) Error() string {
+	return fmt.Sprintf("invalid most items per bin: %v", err.MaxItems)
+}
+
+// InvalidBinTypeError returned when a bin type has a non-positive
+// capacity, or a negative cost or limit
+type InvalidBinTypeError struct {
+	// Index position of the bin type in BinTypes
+	Index   int
+	BinType BinType
+}
+
+func (err *InvalidBinTypeError) Error() string {
+	return fmt.Sprintf("invalid bin type %v: %+v", err.Index, err.BinType)
+}
+
+// InvalidConflictError returned when a conflict names an item
+// outside the input, or the same item twice
+type InvalidConflictError struct {
+	// Index position of the conflict in Conflicts
+	Index    int
+	Conflict Conflict
+}
+
+func (err *InvalidConflictError) Error() string {
+	return fmt.Sprintf("invalid conflict %v: %v", err.Index, err.Conflict)
+}
+
+// InvalidItemError returned when an item has a non-positive size
+type InvalidItemError struct {
+	// Index position of the item in the input, -1 for a single item
+	Index int
+	Item  Item
+}
+
+func (err *InvalidItemError) Error() string {
+	if err.Index < 0 {
+		return fmt.Sprintf("invalid item size: %v", err.Item)
+	}
+	return fmt.Sprintf("invalid size for item %v: %v", err.Index, err.Item)
+}
+
+// OversizeItemError returned when an item is larger than the bin capacity
+type OversizeItemError struct {
+	// Index position of the item in the input, -1 for a single item
+	Index    int
+	Item     Item
+	Capacity Size
+}
+
+func (err *OversizeItemError) Error() string {
+	if err.Index < 0 {
+		return fmt.Sprintf("item of size %v exceeds bin capacity %v", err.Item, err.Capacity)
+	}
+	return fmt.Sprintf("item %v of size %v exceeds bin capacity %v", err.Index, err.Item, err.Capacity)
+}
+
+// InvalidQuantityError returned when a demand has a negative quantity
+type InvalidQuantityError struct {
+	// Index position of the demand in the input
+	Index    int
+	Quantity Count
+}
+
+func (err *InvalidQuantityError) Error() string {
+	return fmt.Sprintf("invalid quantity for demand %v: %v", err.Index, err.Quantity)
+}
+
+// InvalidVectorError returned when an item's sizes are negative or all zero,
+// or it has a different number of dimensions than the bins, or when the
+// bins have a non-positive capacity on some dimension
+type InvalidVectorError struct {
+	// Index position of the item in the input, -1 for the bin capacity
+	Index  int
+	Vector Vector
+}
+
+func (err *InvalidVectorError) Error() string {
+	if err.Index < 0 {
+		return fmt.Sprintf("invalid bin capacity: %v", err.Vector)
+	}
+	return fmt.Sprintf("invalid sizes for item %v: %v", err.Index, err.Vector)
+}
+
+// OversizeVectorError returned when an item exceeds the bin capacity on some dimension
+type OversizeVectorError struct {
+	// Index position of the item in the input
+	Index    int
+	Item     Vector
+	Capacity Vector
+}
+
+func (err *OversizeVectorError) Error() string {
+	return fmt.Sprintf("item %v of size %v exceeds bin capacity %v", err.Index, err.Item, err.Capacity)
+}
+
+// UnknownRuleError returned when no item ordering or bin scoring has the given name
+type UnknownRuleError struct {
+	Rule string
+}
+
+func (err *UnknownRuleError) Error() string {
+	return fmt.Sprintf("unknown rule: %v", err.Rule)
+}
+
+// BinIndexError returned when a bin is requested that does not exist
+type BinIndexError struct {
+	Index     int
+	TotalBins Count
+}
+
+func (err *BinIndexError) Error() string {
+	return fmt.Sprintf("bin index %v out of range for %v bins", err.Index, err.TotalBins)
+}
+
+// UnknownItemError returned when an item is removed from a Session which does not hold it
+type UnknownItemError struct {
+	ID int
+}
+
+func (err *UnknownItemError) Error() string {
+	return fmt.Sprintf("no item with ID %v in the session", err.ID)
+}
+
+// validateItem check that an item can be packed into bins of the given capacity
+func validateItem(index int, item Item, capacity Size) error {
+	if item <= 0 {
+		return &InvalidItemError{Index: index, Item: item}
+	}
+	if Size(item) > capacity {
+		return &OversizeItemError{Index: index, Item: item, Capacity: capacity}
+	}
+	return nil
+}
+
+// validateVector check that an item can be packed into bins of the given capacity
+func validateVector(index int, item Vector, capacity Vector) error {
+	var total Size
+	for _, size := range item {
+		if size < 0 {
+			return &InvalidVectorError{Index: index, Vector: item}
+		}
+		total += size
+	}
+	if total == 0 || len(item) != len(capacity) {
+		return &InvalidVectorError{Index: index, Vector: item}
+	}
+	if !item.fits(capacity) {
+		return &OversizeVectorError{Index: index, Item: item, Capacity: capacity}
+	}
+	return nil
+}
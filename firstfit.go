@@ -1,5 +1,7 @@
 package binpacking
 
+import "context"
+
 // FirstFitPack pack the next item using the first fit algorithm
 func FirstFitPack(binCollection *BinCollectionImpl, item Item) {
 	found := binCollection.Find(func(bin *Bin) bool { return bin.CanFit(item) })
@@ -15,3 +17,42 @@ func FirstFitPack(binCollection *BinCollectionImpl, item Item) {
 func FirstFitDecreasingPack(binCollection *BinCollectionImpl, item Item) {
 	FirstFitPack(binCollection, item)
 }
+
+// firstFitPacker packs all items using first fit, finding the first bin
+// with enough room through a segment tree in O(log n) per item
+type firstFitPacker struct {
+	decreasing bool
+}
+
+// PackAll pack every item in succession
+func (packer firstFitPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	order := items.inputOrder()
+	if packer.decreasing {
+		order = items.decreasingOrder()
+	}
+	tree := newMaxSegmentTree()
+	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
+		tree.Append(binCollection.binAt(i).room())
+	}
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item := items[position]
+		index := tree.FirstAtLeast(Size(item))
+		if index < 0 {
+			binCollection.NewBin()
+			index = int(binCollection.GetTotalBins()) - 1
+			tree.Append(binCollection.binAt(index).room())
+		}
+		bin := binCollection.binAt(index)
+		bin.PackIndex(item, position)
+		tree.Set(index, bin.room())
+	}
+	return nil
+}
+
+// PackItem pack a single item using the linear scan in FirstFitPack
+func (packer firstFitPacker) PackItem(binCollection *BinCollectionImpl, item Item) {
+	FirstFitPack(binCollection, item)
+}
@@ -0,0 +1,227 @@
+package binpacking
+
+import "context"
+
+// harmonicVariant which member of the Harmonic family is packing
+type harmonicVariant int
+
+const (
+	harmonic harmonicVariant = iota
+	refinedHarmonic
+	modifiedHarmonic
+)
+
+// defaultHarmonicK the number of classes used by Lee and Lee for Refined Harmonic
+const defaultHarmonicK = 20
+
+// classes used by Refined and Modified Harmonic for items larger than a third,
+// numbered below the regular harmonic classes 1 through k
+const (
+	harmonicLarge = -1 // too large to share a bin with anything
+	harmonicA     = -2 // just over half, leaving room for one b item
+	harmonicB     = -3 // just over a third
+)
+
+// diverted every seventh b item (and in Modified Harmonic, every seventh
+// small item of classes 3 through 6) is paired with an a item instead of
+// being packed with items of its own class
+const diverted = 7
+
+// harmonicClass classify an item into harmonic interval j when it lies in
+// (capacity/(j+1), capacity/j], or k when it is no larger than capacity/k
+func harmonicClass(item Item, capacity Size, k int) int {
+	for j := 1; j < k; j++ {
+		if Size(item)*Size(j+1) > capacity {
+			return j
+		}
+	}
+	return k
+}
+
+// refinedClass classify an item for Refined or Modified Harmonic, which
+// split items larger than a third at 1-y, 1/2 and y, where y is yNum/yDen
+func refinedClass(item Item, capacity Size, k int, yNum, yDen Size) int {
+	size := Size(item)
+	if size*yDen > (yDen-yNum)*capacity {
+		return harmonicLarge
+	} else if 2*size > capacity {
+		return harmonicA
+	} else if size*yDen > yNum*capacity {
+		return 2
+	} else if 3*size > capacity {
+		return harmonicB
+	}
+	return harmonicClass(item, capacity, k)
+}
+
+// harmonicState the bins kept open by a Harmonic algorithm between items
+type harmonicState struct {
+	variant     harmonicVariant
+	k           int
+	open        map[int]int // index of the bin open for each class
+	waitingA    []int       // bins holding an a item, with room for one more item
+	waitingB    []int       // bins holding a lone b item, waiting for an a item
+	counts      map[int]int // items seen per class, used to divert every seventh
+	openBins    Count
+	maxOpenBins Count
+}
+
+// newHarmonicState create the state for a Harmonic variant with k classes,
+// using the default when k is not positive
+func newHarmonicState(variant harmonicVariant, k int) *harmonicState {
+	if k <= 0 {
+		k = defaultHarmonicK
+	}
+	if variant != harmonic && k < 3 {
+		k = 3 // the refined variants handle everything above a third specially
+	}
+	return &harmonicState{
+		variant: variant,
+		k:       k,
+		open:    make(map[int]int),
+		counts:  make(map[int]int)}
+}
+
+// pack place a single item, recording its position in the input
+// unless it is negative
+func (state *harmonicState) pack(binCollection *BinCollectionImpl, item Item, position int) {
+	var class int
+	switch state.variant {
+	case refinedHarmonic:
+		class = refinedClass(item, binCollection.BinCapacity, state.k, 37, 96)
+	case modifiedHarmonic:
+		class = refinedClass(item, binCollection.BinCapacity, state.k, 265, 684)
+	default:
+		class = harmonicClass(item, binCollection.BinCapacity, state.k)
+	}
+	state.counts[class]++
+
+	switch {
+	case class == harmonicLarge:
+		packAt(state.openBin(binCollection), item, position)
+		state.openBins--
+	case class == harmonicA:
+		if len(state.waitingB) > 0 && binCollection.binAt(state.waitingB[0]).CanFit(item) {
+			packAt(binCollection.binAt(state.waitingB[0]), item, position)
+			state.waitingB = state.waitingB[1:]
+			state.openBins--
+		} else {
+			state.waitingA = state.openWaiting(binCollection, item, position, state.waitingA)
+		}
+	case class == harmonicB && state.counts[class]%diverted == 0:
+		if !state.fillWaitingA(binCollection, item, position) {
+			state.waitingB = state.openWaiting(binCollection, item, position, state.waitingB)
+		}
+	case class == harmonicB:
+		state.packClass(binCollection, class, 2, item, position)
+	case state.variant == modifiedHarmonic && class >= 3 && class <= 6 &&
+		state.counts[class]%diverted == 0 && state.fillWaitingA(binCollection, item, position):
+		// packed alongside an a item
+	case class < state.k:
+		state.packClass(binCollection, class, class, item, position)
+	default:
+		// the smallest items are packed using next fit
+		if index, ok := state.open[class]; ok && binCollection.binAt(index).CanFit(item) {
+			packAt(binCollection.binAt(index), item, position)
+		} else {
+			if ok {
+				state.openBins--
+			}
+			packAt(state.openBin(binCollection), item, position)
+			state.open[class] = int(binCollection.GetTotalBins()) - 1
+		}
+	}
+}
+
+// packClass pack an item into the bin open for its class, which
+// is closed once it holds the given number of items
+func (state *harmonicState) packClass(binCollection *BinCollectionImpl, class, perBin int, item Item, position int) {
+	index, ok := state.open[class]
+	if !ok {
+		state.openBin(binCollection)
+		index = int(binCollection.GetTotalBins()) - 1
+		state.open[class] = index
+	}
+	bin := binCollection.binAt(index)
+	packAt(bin, item, position)
+	if len(bin.Items) >= perBin || !bin.hasSlots(1) {
+		delete(state.open, class)
+		state.openBins--
+	}
+}
+
+// fillWaitingA pack an item into the oldest bin holding an a item, closing it.
+// Returns false if no such bin can fit the item.
+func (state *harmonicState) fillWaitingA(binCollection *BinCollectionImpl, item Item, position int) bool {
+	if len(state.waitingA) == 0 || !binCollection.binAt(state.waitingA[0]).CanFit(item) {
+		return false
+	}
+	packAt(binCollection.binAt(state.waitingA[0]), item, position)
+	state.waitingA = state.waitingA[1:]
+	state.openBins--
+	return true
+}
+
+// openWaiting pack an item into a new bin, adding the bin to those given to
+// wait for an item to share it, unless it already holds as many items as a
+// bin may, in which case it is closed
+func (state *harmonicState) openWaiting(binCollection *BinCollectionImpl, item Item, position int, waiting []int) []int {
+	bin := state.openBin(binCollection)
+	packAt(bin, item, position)
+	if !bin.hasSlots(1) {
+		state.openBins--
+		return waiting
+	}
+	return append(waiting, int(binCollection.GetTotalBins())-1)
+}
+
+// packAt pack an item into a bin, with its position in the input unless it is negative
+func packAt(bin *Bin, item Item, position int) {
+	if position < 0 {
+		bin.Pack(item)
+		return
+	}
+	bin.PackIndex(item, position)
+}
+
+// openBin create a new bin, keeping track of how many are open
+func (state *harmonicState) openBin(binCollection *BinCollectionImpl) *Bin {
+	state.openBins++
+	if state.openBins > state.maxOpenBins {
+		state.maxOpenBins = state.openBins
+	}
+	return binCollection.NewBin()
+}
+
+// harmonicPacker packs items online with a Harmonic variant, using
+// the collection's K option for the number of classes. The bins left
+// open are kept on the collection, so PackItem carries on from them.
+type harmonicPacker struct {
+	variant harmonicVariant
+}
+
+// PackAll pack every item in succession
+func (packer harmonicPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	state := newHarmonicState(packer.variant, binCollection.K)
+	for position, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		state.pack(binCollection, item, position)
+	}
+	binCollection.MaxOpenBins = state.maxOpenBins
+	binCollection.harmonic = state
+	return nil
+}
+
+// PackItem pack a single item into the bins left open by the items packed
+// before it with the same variant
+func (packer harmonicPacker) PackItem(binCollection *BinCollectionImpl, item Item) {
+	state := binCollection.harmonic
+	if state == nil || state.variant != packer.variant {
+		state = newHarmonicState(packer.variant, binCollection.K)
+		binCollection.harmonic = state
+	}
+	state.pack(binCollection, item, -1)
+	binCollection.MaxOpenBins = state.maxOpenBins
+}
@@ -0,0 +1,217 @@
+package binpacking
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+)
+
+// defaultGenerations the number of generations the genetic algorithm runs for by default
+const defaultGenerations = 200
+
+// defaultPopulation the number of packings the genetic algorithm evolves by default
+const defaultPopulation = 50
+
+// mutatedBins how many bins a mutation dissolves besides the least filled one
+const mutatedBins = 2
+
+// hggaIndividual a packing evolved by the genetic algorithm
+type hggaIndividual struct {
+	bins    []*lsBin
+	fitness float64
+}
+
+// newIndividual create an individual, rating it with Falkenauer's fitness:
+// the average over its bins of the squared fraction of the bin in use,
+// which prefers a few full bins to many partly full ones
+func newIndividual(bins []*lsBin, capacity Size) *hggaIndividual {
+	var sum float64
+	for _, bin := range bins {
+		fill := float64(bin.load) / float64(capacity)
+		sum += fill * fill
+	}
+	return &hggaIndividual{bins: bins, fitness: sum / float64(len(bins))}
+}
+
+// PackAllGenetic pack all items using Falkenauer's hybrid grouping genetic
+// algorithm (HGGA). Packings are made of whole bins, so crossover copies a
+// run of bins from one parent into the other, dissolving the bins of the
+// other parent which share items with them, and mutation dissolves the least
+// filled bin and a few random ones. The items of dissolved bins are put back
+// by exchanging them for smaller items where that makes a bin fuller, then
+// with best fit decreasing. The search stops after the configured number of
+// generations, when the time limit or context is done, or once it reaches
+// a lower bound, in which case the status is set to Optimal.
+// The same seed always gives the same packing, unless stopped by time.
+func (binCollection *BinCollectionImpl) PackAllGenetic(ctx context.Context, items Items) {
+	limits := binCollection.limits()
+	capacity := limits.capacity
+	ctx, cancel := binCollection.withTimeLimit(ctx)
+	defer cancel()
+	generations := binCollection.Generations
+	if generations <= 0 {
+		generations = defaultGenerations
+	}
+	size := binCollection.Population
+	if size <= 0 {
+		size = defaultPopulation
+	} else if size < 2 {
+		size = 2 // crossover needs two parents
+	}
+	r := rand.New(rand.NewSource(binCollection.Seed))
+	floor := LowerBoundCardinality(items, capacity, limits.maxItems)
+	if bound := LowerBoundDFF(items, capacity); bound > floor {
+		floor = bound
+	}
+
+	free := make([]lsItem, len(items))
+	for i, item := range items {
+		free[i] = lsItem{Size(item), i}
+	}
+	// one packing by best fit decreasing, the rest by first fit in random orders
+	population := []*hggaIndividual{newIndividual(placeFree(ctx, nil, free, limits), capacity)}
+	for len(population) < size {
+		r.Shuffle(len(free), func(i, j int) { free[i], free[j] = free[j], free[i] })
+		population = append(population, newIndividual(randomFirstFit(free, limits), capacity))
+	}
+	best := population[0]
+	for _, individual := range population {
+		if len(individual.bins) < len(best.bins) {
+			best = individual
+		}
+	}
+	binCollection.reportBest(Count(len(best.bins)))
+
+	for generation := 0; generation < generations && Count(len(best.bins)) > floor && ctx.Err() == nil; generation++ {
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].fitness > population[j].fitness
+		})
+		// the children of half the population replace the less fit half
+		children := make([]*hggaIndividual, 0, size/2+1)
+		for len(children) < size/2 {
+			first, second := tournament(r, population), tournament(r, population)
+			children = append(children,
+				crossover(ctx, r, first, second, limits),
+				crossover(ctx, r, second, first, limits))
+		}
+		copy(population[size-size/2:], children)
+		// the fittest packing is never mutated, so it is not lost
+		for i := 1; i < size; i++ {
+			if r.Intn(size) < size/10+1 {
+				population[i] = mutate(ctx, r, population[i], limits)
+			}
+		}
+		improved := false
+		for _, individual := range population {
+			if len(individual.bins) < len(best.bins) {
+				best, improved = individual, true
+			}
+		}
+		if improved {
+			binCollection.reportBest(Count(len(best.bins)))
+		}
+	}
+
+	binCollection.setBins(best.bins)
+	if Count(len(best.bins)) <= floor {
+		binCollection.Status = Optimal
+	}
+}
+
+// randomFirstFit pack items with first fit in the order given
+func randomFirstFit(items []lsItem, limits binLimits) []*lsBin {
+	bins := make([]*lsBin, 0)
+	for _, item := range items {
+		placed := false
+		for _, bin := range bins {
+			if limits.fits(bin.load+item.size, len(bin.items)+1) {
+				bin.items = append(bin.items, item)
+				bin.load += item.size
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			bins = append(bins, &lsBin{items: []lsItem{item}, load: item.size})
+		}
+	}
+	return bins
+}
+
+// tournament choose the fitter of two random individuals
+func tournament(r *rand.Rand, population []*hggaIndividual) *hggaIndividual {
+	first, second := population[r.Intn(len(population))], population[r.Intn(len(population))]
+	if second.fitness > first.fitness {
+		return second
+	}
+	return first
+}
+
+// crossover copy a random run of the second parent's bins into a random
+// point of the first parent's, dissolving the first parent's bins which
+// hold any item of the copied run and putting their other items back
+func crossover(ctx context.Context, r *rand.Rand, first, second *hggaIndividual, limits binLimits) *hggaIndividual {
+	start := r.Intn(len(second.bins))
+	section := second.bins[start : start+1+r.Intn(len(second.bins)-start)]
+	copied := make(map[int]bool)
+	for _, bin := range section {
+		for _, item := range bin.items {
+			copied[item.index] = true
+		}
+	}
+
+	point := r.Intn(len(first.bins) + 1)
+	bins := make([]*lsBin, 0, len(first.bins)+len(section))
+	free := make([]lsItem, 0)
+	for i := 0; i <= len(first.bins); i++ {
+		if i == point {
+			for _, bin := range section {
+				bins = append(bins, bin.clone())
+			}
+		}
+		if i == len(first.bins) {
+			break
+		}
+		bin := first.bins[i]
+		dissolve := false
+		for _, item := range bin.items {
+			dissolve = dissolve || copied[item.index]
+		}
+		if !dissolve {
+			bins = append(bins, bin.clone())
+			continue
+		}
+		for _, item := range bin.items {
+			if !copied[item.index] {
+				free = append(free, item)
+			}
+		}
+	}
+	return newIndividual(append(bins, placeFree(ctx, bins, free, limits, exchangeFree, insertFree)...), limits.capacity)
+}
+
+// mutate dissolve the least filled bin and a few random others, putting their items back
+func mutate(ctx context.Context, r *rand.Rand, individual *hggaIndividual, limits binLimits) *hggaIndividual {
+	dissolved := make(map[int]bool)
+	least := 0
+	for i, bin := range individual.bins {
+		if bin.load < individual.bins[least].load {
+			least = i
+		}
+	}
+	dissolved[least] = true
+	for i := 0; i < mutatedBins; i++ {
+		dissolved[r.Intn(len(individual.bins))] = true
+	}
+
+	bins := make([]*lsBin, 0, len(individual.bins))
+	free := make([]lsItem, 0)
+	for i, bin := range individual.bins {
+		if dissolved[i] {
+			free = append(free, bin.items...)
+		} else {
+			bins = append(bins, bin.clone())
+		}
+	}
+	return newIndividual(append(bins, placeFree(ctx, bins, free, limits, exchangeFree, insertFree)...), limits.capacity)
+}
@@ -0,0 +1,370 @@
+package binpacking
+
+import (
+	"context"
+	"sort"
+)
+
+// improvementSuffix appended to the name of an algorithm to follow it with Improve
+const improvementSuffix = "+LS"
+
+// improveTargets the most of the least filled bins Improve empties at once
+const improveTargets = 3
+
+// refillNodeLimit the most subsets the Minimum Bin Slack refill enumerates
+// for a single bin before settling for the best one found
+const refillNodeLimit = 10000
+
+// binLimits what any bin may hold: items up to its capacity in total,
+// and no more than maxItems of them when that is set
+type binLimits struct {
+	capacity Size
+	maxItems Count
+}
+
+// limits the limits on every bin of the collection
+func (binCollection *BinCollectionImpl) limits() binLimits {
+	return binLimits{capacity: binCollection.BinCapacity, maxItems: binCollection.MaxItems}
+}
+
+// fits whether a bin of the given load and number of items is within the limits
+func (limits binLimits) fits(load Size, count int) bool {
+	return load <= limits.capacity && (limits.maxItems <= 0 || Count(count) <= limits.maxItems)
+}
+
+// lsItem an item moved around by the local search, with its input position
+// (-1 when the packing did not record one)
+type lsItem struct {
+	size  Size
+	index int
+}
+
+// lsBin a bin being rearranged by the local search
+type lsBin struct {
+	items []lsItem
+	load  Size
+}
+
+// clone copy a bin, so the copy can be changed without affecting the original
+func (bin *lsBin) clone() *lsBin {
+	copied := &lsBin{items: make([]lsItem, len(bin.items)), load: bin.load}
+	copy(copied.items, bin.items)
+	return copied
+}
+
+// Improve post-optimize a completed packing by emptying its least filled
+// bins and redistributing their items over the other bins. The free items
+// are inserted where they fit, exchanged one or two at a time for smaller
+// items packed elsewhere, or used to refill other bins with the Minimum Bin
+// Slack heuristic, so the other bins get fuller and the items left over get
+// fewer and smaller. Those are packed into new bins with best fit
+// decreasing, and the result is kept if it uses fewer bins than before, or
+// as many bins with a larger sum of squared loads, since concentrating the
+// items in fuller bins makes the least filled ones easier to empty later.
+// Up to three of the least filled bins are emptied at a time, and this
+// repeats until no such change is found. Every item keeps its input position.
+// Stops early if the context is done. Returns the number of bins saved.
+func (binCollection *BinCollectionImpl) Improve(ctx context.Context) Count {
+	limits := binCollection.limits()
+	bins := make([]*lsBin, 0, len(binCollection.Bins))
+	all := make(Items, 0)
+	for _, bin := range binCollection.Bins {
+		current := &lsBin{load: bin.Usage}
+		for j, item := range bin.Items {
+			index := -1
+			if j < len(bin.Indices) {
+				index = bin.Indices[j]
+			}
+			current.items = append(current.items, lsItem{Size(item), index})
+		}
+		bins = append(bins, current)
+		all = append(all, bin.Items...)
+	}
+	floor := LowerBoundCardinality(all, limits.capacity, limits.maxItems)
+
+	saved := Count(0)
+	for Count(len(bins)) > floor && ctx.Err() == nil {
+		sort.SliceStable(bins, func(i, j int) bool { return bins[i].load < bins[j].load })
+		improved := false
+		for targets := 1; targets <= improveTargets && targets < len(bins); targets++ {
+			if repacked, ok := redistribute(ctx, bins, targets, limits); ok {
+				saved += Count(len(bins) - len(repacked))
+				bins = repacked
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	binCollection.setBins(bins)
+	return saved
+}
+
+// setBins replace the collection's bins with those of a local search
+func (binCollection *BinCollectionImpl) setBins(bins []*lsBin) {
+	binCollection.Bins = make(Bins, len(bins))
+	for i, current := range bins {
+		// items without a position go last, since Indices only covers a prefix of Items
+		sort.SliceStable(current.items, func(a, b int) bool {
+			return current.items[a].index >= 0 && current.items[b].index < 0
+		})
+		bin := NewBin(binCollection.BinCapacity)
+		bin.MaxItems = binCollection.MaxItems
+		for _, item := range current.items {
+			if item.index >= 0 {
+				bin.PackIndex(Item(item.size), item.index)
+			} else {
+				bin.Pack(Item(item.size))
+			}
+		}
+		binCollection.Bins[i] = bin
+	}
+	binCollection.TotalBins = Count(len(bins))
+}
+
+// redistribute empty the given number of bins from the start of the list,
+// moving their items into the other bins as far as possible and packing the
+// rest into new bins. Returns the new bins, or false if they are neither
+// fewer nor fuller than before, in which case the bins passed in are left
+// untouched.
+func redistribute(ctx context.Context, bins []*lsBin, targets int, limits binLimits) ([]*lsBin, bool) {
+	free := make([]lsItem, 0)
+	for _, bin := range bins[:targets] {
+		free = append(free, bin.items...)
+	}
+	others := make([]*lsBin, 0, len(bins))
+	for _, bin := range bins[targets:] {
+		others = append(others, bin.clone())
+	}
+
+	added := placeFree(ctx, others, free, limits, insertFree, exchangeFree, refillFree)
+	repacked := append(others, added...)
+	if len(added) > targets || (len(added) == targets && fillSquared(repacked) <= fillSquared(bins)) {
+		return nil, false
+	}
+	return repacked, true
+}
+
+// lsMove a local search move which places some of the free items,
+// possibly freeing smaller ones, returning false if it found nothing to do
+type lsMove func(bins []*lsBin, free *[]lsItem, limits binLimits) bool
+
+// placeFree apply the first move which does something until none does or the
+// context is done, then pack the free items left into new bins with best fit
+// decreasing. Returns the new bins.
+func placeFree(ctx context.Context, bins []*lsBin, free []lsItem, limits binLimits, moves ...lsMove) []*lsBin {
+	// each move leaves less free space to place, so this terminates
+	for len(free) > 0 && ctx.Err() == nil {
+		moved := false
+		for _, move := range moves {
+			if move(bins, &free, limits) {
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool { return free[i].size > free[j].size })
+	added := make([]*lsBin, 0)
+	for _, item := range free {
+		if !insertFree(added, &[]lsItem{item}, limits) {
+			added = append(added, &lsBin{items: []lsItem{item}, load: item.size})
+		}
+	}
+	return added
+}
+
+// insertFree pack the largest free item that fits anywhere into the
+// fullest bin it fits in
+func insertFree(bins []*lsBin, free *[]lsItem, limits binLimits) bool {
+	sort.SliceStable(*free, func(i, j int) bool { return (*free)[i].size > (*free)[j].size })
+	for i, item := range *free {
+		best := -1
+		for b, bin := range bins {
+			if limits.fits(bin.load+item.size, len(bin.items)+1) && (best < 0 || bin.load > bins[best].load) {
+				best = b
+			}
+		}
+		if best >= 0 {
+			bins[best].items = append(bins[best].items, item)
+			bins[best].load += item.size
+			*free = append((*free)[:i], (*free)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// exchangeFree swap one or two items of a bin for one or two larger free
+// items, making that bin fuller. The first bin with such an exchange takes
+// the one filling it the most.
+func exchangeFree(bins []*lsBin, free *[]lsItem, limits binLimits) bool {
+	// subsets of one and of two free items, each in increasing order of
+	// size, so the largest that fits in a bin can be found by binary search
+	ins := make([]subsetsBySize, 3)
+	for _, in := range smallSubsets(len(*free)) {
+		ins[len(in)].subsets = append(ins[len(in)].subsets, in)
+		ins[len(in)].sizes = append(ins[len(in)].sizes, subsetSize(*free, in))
+	}
+	for _, group := range ins {
+		sort.Sort(group)
+	}
+
+	for _, bin := range bins {
+		var bestOut, bestIn []int
+		bestLoad := bin.load
+		for _, out := range smallSubsets(len(bin.items)) {
+			outSize := subsetSize(bin.items, out)
+			room := limits.capacity - bin.load + outSize
+			for count := 1; count <= 2; count++ {
+				if !limits.fits(0, len(bin.items)-len(out)+count) {
+					continue
+				}
+				sizes := ins[count].sizes
+				fits := sort.Search(len(sizes), func(i int) bool { return sizes[i] > room }) - 1
+				if fits >= 0 && bin.load-outSize+sizes[fits] > bestLoad {
+					bestOut, bestIn, bestLoad = out, ins[count].subsets[fits], bin.load-outSize+sizes[fits]
+				}
+			}
+		}
+		if bestOut == nil {
+			continue
+		}
+		leaving := takeSubset(&bin.items, bestOut)
+		bin.items = append(bin.items, takeSubset(free, bestIn)...)
+		*free = append(*free, leaving...)
+		bin.load = bestLoad
+		return true
+	}
+	return false
+}
+
+// subsetsBySize sort.Interface ordering subsets by their total size
+type subsetsBySize struct {
+	subsets [][]int
+	sizes   []Size
+}
+
+func (s subsetsBySize) Len() int           { return len(s.subsets) }
+func (s subsetsBySize) Less(i, j int) bool { return s.sizes[i] < s.sizes[j] }
+func (s subsetsBySize) Swap(i, j int) {
+	s.subsets[i], s.subsets[j] = s.subsets[j], s.subsets[i]
+	s.sizes[i], s.sizes[j] = s.sizes[j], s.sizes[i]
+}
+
+// refillFree refill a bin from its own items and the free items, choosing
+// the combination with the minimum slack, as in the Minimum Bin Slack
+// heuristic of Gupta and Ho. Used for exchanges larger than two for two.
+func refillFree(bins []*lsBin, free *[]lsItem, limits binLimits) bool {
+	for _, bin := range bins {
+		candidates := make([]lsItem, 0, len(bin.items)+len(*free))
+		candidates = append(candidates, bin.items...)
+		candidates = append(candidates, *free...)
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].size > candidates[j].size })
+
+		chosen := make([]bool, len(candidates))
+		best := make([]bool, len(candidates))
+		bestLoad := bin.load
+		nodes := 0
+		var search func(next int, load Size, count int)
+		search = func(next int, load Size, count int) {
+			nodes++
+			if load > bestLoad {
+				bestLoad = load
+				copy(best, chosen)
+			}
+			for i := next; i < len(candidates) && bestLoad < limits.capacity && nodes < refillNodeLimit; i++ {
+				if limits.fits(load+candidates[i].size, count+1) {
+					chosen[i] = true
+					search(i+1, load+candidates[i].size, count+1)
+					chosen[i] = false
+				}
+			}
+		}
+		search(0, 0, 0)
+		if bestLoad == bin.load {
+			continue
+		}
+		bin.items = bin.items[:0]
+		*free = (*free)[:0]
+		for i, item := range candidates {
+			if best[i] {
+				bin.items = append(bin.items, item)
+			} else {
+				*free = append(*free, item)
+			}
+		}
+		bin.load = bestLoad
+		return true
+	}
+	return false
+}
+
+// fillSquared sum of the squared loads of the bins, which grows
+// as items are concentrated in fewer, fuller bins
+func fillSquared(bins []*lsBin) Size {
+	var sum Size
+	for _, bin := range bins {
+		sum += bin.load * bin.load
+	}
+	return sum
+}
+
+// smallSubsets every subset of one or two positions out of n
+func smallSubsets(n int) [][]int {
+	subsets := make([][]int, 0, n*(n+1)/2)
+	for i := 0; i < n; i++ {
+		subsets = append(subsets, []int{i})
+		for j := i + 1; j < n; j++ {
+			subsets = append(subsets, []int{i, j})
+		}
+	}
+	return subsets
+}
+
+// subsetSize total size of the items at the given positions
+func subsetSize(items []lsItem, subset []int) Size {
+	var size Size
+	for _, i := range subset {
+		size += items[i].size
+	}
+	return size
+}
+
+// takeSubset remove the items at the given increasing positions, returning them
+func takeSubset(items *[]lsItem, subset []int) []lsItem {
+	taken := make([]lsItem, 0, len(subset))
+	for k := len(subset) - 1; k >= 0; k-- {
+		i := subset[k]
+		taken = append(taken, (*items)[i])
+		*items = append((*items)[:i], (*items)[i+1:]...)
+	}
+	return taken
+}
+
+// improvingPacker packs items with another algorithm, then runs Improve
+type improvingPacker struct {
+	base Algorithm
+}
+
+// PackAll pack every item with the base algorithm, then improve the result
+func (packer improvingPacker) PackAll(ctx context.Context, binCollection *BinCollectionImpl, items Items) error {
+	base := packer.base.Packer()
+	if base == nil {
+		return &UnknownAlgorithmError{Algorithm: packer.base}
+	}
+	if err := base.PackAll(ctx, binCollection, items); err != nil {
+		return err
+	}
+	binCollection.cleanupBins()
+	binCollection.assignIndices(items)
+	if binCollection.Status != Optimal {
+		binCollection.Improve(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package binpacking
+
+// maxSegmentTree a segment tree over the remaining space of each bin,
+// used to find the first bin with enough room in O(log n)
+type maxSegmentTree struct {
+	count  int    // number of bins in the tree
+	leaves int    // number of leaves allocated, always a power of two
+	tree   []Size // tree[1] is the root, leaves start at tree[leaves]
+}
+
+// newMaxSegmentTree create an empty segment tree
+func newMaxSegmentTree() *maxSegmentTree {
+	tree := &maxSegmentTree{leaves: 1}
+	tree.tree = []Size{-1, -1}
+	return tree
+}
+
+// Append add a new bin with the given remaining space
+func (tree *maxSegmentTree) Append(remaining Size) {
+	if tree.count == tree.leaves {
+		tree.grow()
+	}
+	tree.count++
+	tree.Set(tree.count-1, remaining)
+}
+
+// Set update the remaining space of the bin at index
+func (tree *maxSegmentTree) Set(index int, remaining Size) {
+	node := tree.leaves + index
+	tree.tree[node] = remaining
+	for node > 1 {
+		node /= 2
+		tree.tree[node] = maxSize(tree.tree[2*node], tree.tree[2*node+1])
+	}
+}
+
+// FirstAtLeast index of the first bin with at least the given remaining space,
+// -1 if there is none
+func (tree *maxSegmentTree) FirstAtLeast(remaining Size) int {
+	if tree.tree[1] < remaining {
+		return -1
+	}
+	node := 1
+	for node < tree.leaves {
+		if tree.tree[2*node] >= remaining {
+			node = 2 * node
+		} else {
+			node = 2*node + 1
+		}
+	}
+	return node - tree.leaves
+}
+
+// grow double the number of leaves, rebuilding the tree
+func (tree *maxSegmentTree) grow() {
+	old := tree.tree[tree.leaves : tree.leaves+tree.count]
+	tree.leaves *= 2
+	tree.tree = make([]Size, 2*tree.leaves)
+	for i := range tree.tree {
+		tree.tree[i] = -1 // unused leaves cannot fit anything
+	}
+	copy(tree.tree[tree.leaves:], old)
+	for node := tree.leaves - 1; node >= 1; node-- {
+		tree.tree[node] = maxSize(tree.tree[2*node], tree.tree[2*node+1])
+	}
+}
+
+func maxSize(a, b Size) Size {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// binTreap a balanced ordered multiset of bins keyed on remaining space,
+// then bin index, used to find the tightest bin with enough room in O(log n)
+type binTreap struct {
+	root *treapNode
+	seed uint64 // state of the priority generator, so results are reproducible
+}
+
+type treapNode struct {
+	remaining   Size
+	index       int
+	priority    uint64
+	left, right *treapNode
+}
+
+// less ordering of treap keys
+func (node *treapNode) less(remaining Size, index int) bool {
+	return node.remaining < remaining || (node.remaining == remaining && node.index < index)
+}
+
+// Insert add a bin with the given remaining space
+func (treap *binTreap) Insert(remaining Size, index int) {
+	// splitmix64 generator for node priorities
+	treap.seed += 0x9e3779b97f4a7c15
+	priority := treap.seed
+	priority = (priority ^ (priority >> 30)) * 0xbf58476d1ce4e5b9
+	priority = (priority ^ (priority >> 27)) * 0x94d049bb133111eb
+	priority ^= priority >> 31
+	node := &treapNode{remaining: remaining, index: index, priority: priority}
+	left, right := splitTreap(treap.root, remaining, index)
+	treap.root = mergeTreap(mergeTreap(left, node), right)
+}
+
+// Delete remove the bin with the given remaining space and index
+func (treap *binTreap) Delete(remaining Size, index int) {
+	left, rest := splitTreap(treap.root, remaining, index)
+	_, right := splitTreap(rest, remaining, index+1)
+	treap.root = mergeTreap(left, right)
+}
+
+// Ceiling index of the bin with the least remaining space that is at least
+// the given amount, preferring the lowest index among ties. Returns -1 if none.
+func (treap *binTreap) Ceiling(remaining Size) int {
+	found := -1
+	node := treap.root
+	for node != nil {
+		if node.remaining >= remaining {
+			found = node.index
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return found
+}
+
+// splitTreap split into nodes ordered before (remaining, index) and the rest
+func splitTreap(node *treapNode, remaining Size, index int) (*treapNode, *treapNode) {
+	if node == nil {
+		return nil, nil
+	}
+	if node.less(remaining, index) {
+		left, right := splitTreap(node.right, remaining, index)
+		node.right = left
+		return node, right
+	}
+	left, right := splitTreap(node.left, remaining, index)
+	node.left = right
+	return left, node
+}
+
+// mergeTreap merge two treaps where every key in left is ordered before right
+func mergeTreap(left, right *treapNode) *treapNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.priority > right.priority {
+		left.right = mergeTreap(left.right, right)
+		return left
+	}
+	right.left = mergeTreap(left, right.left)
+	return right
+}
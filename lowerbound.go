@@ -1,20 +1,66 @@
 package binpacking
 
-import (
-	"math"
-	"sort"
-)
+import "sort"
+
+// LowerBounder a method of bounding the minimum (optimal) number
+// of bins needed to pack a problem instance
+type LowerBounder interface {
+	// LowerBound a number of bins no larger than the optimum.
+	// The items passed in are not modified.
+	LowerBound(items Items, binSize Size) Count
+}
+
+// LowerBoundFunc adapter allowing an ordinary function to be used as a LowerBounder
+type LowerBoundFunc func(items Items, binSize Size) Count
+
+// LowerBound call the underlying function
+func (bound LowerBoundFunc) LowerBound(items Items, binSize Size) Count {
+	return bound(items, binSize)
+}
+
+// lowerBounders the bounds combined by BestLowerBound
+var lowerBounders = []LowerBounder{
+	LowerBoundFunc(LowerBoundL1),
+	LowerBoundFunc(LowerBoundL2),
+	LowerBoundFunc(LowerBoundL3),
+	LowerBoundFunc(LowerBoundDFF),
+	LowerBoundFunc(CalculateLowerBound)}
+
+// BestLowerBound the tightest of the built in lower bounds and any
+// others given. L3 reduces the problem once per item, so on instances of
+// many thousands of items it takes far longer than the other bounds.
+// LowerBoundLP is slower still, so it is only used when given.
+func BestLowerBound(items Items, binSize Size, others ...LowerBounder) Count {
+	best := Count(0)
+	for _, bounder := range append(lowerBounders, others...) {
+		if bound := bounder.LowerBound(items, binSize); bound > best {
+			best = bound
+		}
+	}
+	return best
+}
+
+// LowerBoundL1 the continuous lower bound: the total size
+// of all items divided by the bin size, rounded up
+func LowerBoundL1(items Items, binSize Size) Count {
+	var sum Size
+	for _, item := range items {
+		sum += Size(item)
+	}
+	return Count(ceilDivide(sum, binSize))
+}
 
 // CalculateLowerBound calculate the estimated lower bound
 // on the minimum (optimal) number of bins that should be
 // used in a problem instance.
 // This is the Estimated Wasted Space algorithm used by Richard E. Korf
 // in his first Bin Completion paper.
+// The items passed in are not modified.
 func CalculateLowerBound(items Items, binSize Size) Count {
-	sort.Sort(sort.Reverse(items)) // sort items in decreasing order
-	waste := 0                     // total space wasted in the ideal solution
-	j := 1                         // j = pointer to end of items list
-	carry := 0                     // carry over for sums
+	items = items.SortedDecreasing() // sort items in decreasing order
+	waste := 0                       // total space wasted in the ideal solution
+	j := 1                           // j = pointer to end of items list
+	carry := 0                       // carry over for sums
 	itemSum := 0
 	for i := 0; i <= len(items)-j; i++ {
 		x := items[i]              // iterate over every item x
@@ -57,5 +103,198 @@ func CalculateLowerBound(items Items, binSize Size) Count {
 		}
 	}
 	// return (sum of items + waste) divided by the bin size, rounded up
-	return Count(int(math.Round(float64(itemSum+waste) / float64(int(binSize)))))
+	return Count(ceilDivide(Size(itemSum+waste), binSize))
+}
+
+// LowerBoundCardinality a lower bound on the number of bins needed when no
+// bin may hold more than maxItems items. It extends LowerBoundL2 by counting
+// items as well as their sizes: for every threshold alpha, the bins of items
+// larger than half have room for maxItems-1 more items each, none of them of
+// at least alpha when the item is larger than binSize-alpha, so the items
+// which find no room there need a bin for every maxItems of them. With no
+// limit on the number of items this is LowerBoundL2.
+// The items passed in are not modified.
+func LowerBoundCardinality(items Items, binSize Size, maxItems Count) Count {
+	if maxItems <= 0 {
+		return LowerBoundL2(items, binSize)
+	}
+	sorted := items.SortedDecreasing()
+	// prefix[i] = sum of the i largest items
+	prefix := make([]Size, len(sorted)+1)
+	for i, item := range sorted {
+		prefix[i+1] = prefix[i] + Size(item)
+	}
+	countAbove := func(size Size) int {
+		return sort.Search(len(sorted), func(i int) bool { return Size(sorted[i]) <= size })
+	}
+	countAtLeast := func(size Size) int {
+		return sort.Search(len(sorted), func(i int) bool { return Size(sorted[i]) < size })
+	}
+	slots := int(maxItems) - 1 // items which may join one larger than half
+
+	half := countAbove(binSize / 2)
+	best := Count(0)
+	for i := len(sorted); i >= 0; i-- {
+		alpha := Size(0)
+		if i < len(sorted) {
+			alpha = Size(sorted[i])
+			if 2*alpha > binSize {
+				break
+			}
+		}
+		large := countAbove(binSize - alpha)
+		medium := half - large
+		small := countAtLeast(alpha) - half
+		tiny := len(sorted) - half - small // items smaller than alpha
+		mediumFree := Size(medium)*binSize - (prefix[half] - prefix[large])
+		smallSum := prefix[half+small] - prefix[half]
+		extra := Count(0)
+		if smallSum > mediumFree {
+			extra = Count(ceilDivide(smallSum-mediumFree, binSize))
+		}
+		// small items only join medium ones, tiny items join either
+		for _, left := range []int{small - medium*slots, small + tiny - half*slots} {
+			if left > 0 {
+				if bins := Count(ceilDivide(Size(left), Size(maxItems))); bins > extra {
+					extra = bins
+				}
+			}
+		}
+		if bound := Count(large+medium) + extra; bound > best {
+			best = bound
+		}
+	}
+	return best
+}
+
+// LowerBoundL2 Martello and Toth's L2 lower bound. For every threshold
+// alpha up to half the bin size, items larger than binSize-alpha need a bin
+// of their own, items larger than half need one each, and items of at least
+// alpha which do not fit in the space left by the second group need more bins.
+func LowerBoundL2(items Items, binSize Size) Count {
+	sorted := items.SortedDecreasing()
+	// prefix[i] = sum of the i largest items
+	prefix := make([]Size, len(sorted)+1)
+	for i, item := range sorted {
+		prefix[i+1] = prefix[i] + Size(item)
+	}
+	// countAbove number of items strictly larger than size
+	countAbove := func(size Size) int {
+		return sort.Search(len(sorted), func(i int) bool { return Size(sorted[i]) <= size })
+	}
+	// countAtLeast number of items no smaller than size
+	countAtLeast := func(size Size) int {
+		return sort.Search(len(sorted), func(i int) bool { return Size(sorted[i]) < size })
+	}
+
+	half := countAbove(binSize / 2)
+	best := Count(0)
+	// alpha only needs to take the sizes of items no larger than half a bin
+	for i := len(sorted); i >= 0; i-- {
+		alpha := Size(0) // checked first, giving at least the continuous bound
+		if i < len(sorted) {
+			alpha = Size(sorted[i])
+			if 2*alpha > binSize {
+				break
+			}
+		}
+		large := countAbove(binSize - alpha) // items which fit with nothing of size alpha
+		medium := half - large               // items over half which may share with small ones
+		small := countAtLeast(alpha) - half  // items between alpha and half
+		mediumFree := Size(medium)*binSize - (prefix[half] - prefix[large])
+		smallSum := prefix[half+small] - prefix[half]
+		bound := Count(large + medium)
+		if smallSum > mediumFree {
+			bound += Count(ceilDivide(smallSum-mediumFree, binSize))
+		}
+		if bound > best {
+			best = bound
+		}
+	}
+	return best
+}
+
+// LowerBoundL3 Martello and Toth's L3 lower bound: reduce the problem and
+// bound what is left with L2, then drop the smallest remaining item and
+// repeat, since each smaller problem needs no more bins than the original
+func LowerBoundL3(items Items, capacity Size) Count {
+	best := Count(0)
+	var reduced Count // bins fixed by all reductions so far
+	for len(items) > 0 {
+		fixed, free := reduce(items, capacity)
+		reduced += Count(len(fixed))
+		rest := make(Items, len(free))
+		for i, position := range free {
+			rest[i] = items[position] // free is in decreasing order of size
+		}
+		if bound := reduced + LowerBoundL2(rest, capacity); bound > best {
+			best = bound
+		}
+		if len(rest) == 0 {
+			break
+		}
+		items = rest[:len(rest)-1]
+	}
+	return best
+}
+
+// feketeSchepersK the largest k used for the u^(k) dual feasible functions
+const feketeSchepersK = 20
+
+// LowerBoundDFF Fekete and Schepers' lower bound using dual feasible
+// functions, which map item sizes so that any set of items fitting in a bin
+// still fits after mapping, making the continuous bound of the mapped items
+// a lower bound. Items are first mapped with U^(e), which rounds items larger
+// than binSize-e up to a full bin and drops items smaller than e, then with
+// u^(k), which rounds sizes down to a multiple of binSize/k unless (k+1)*size
+// is a multiple of binSize. Every e up to half a bin and k up to 20 is tried.
+func LowerBoundDFF(items Items, binSize Size) Count {
+	// distinct sizes in increasing order, with the number of items of each
+	counts := make(map[Size]Size)
+	for _, item := range items {
+		counts[Size(item)]++
+	}
+	sizes := make([]Size, 0, len(counts))
+	for size := range counts {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	// countAbove[i] number of items in sizes[i:]
+	countAbove := make([]Size, len(sizes)+1)
+	for i := len(sizes) - 1; i >= 0; i-- {
+		countAbove[i] = countAbove[i+1] + counts[sizes[i]]
+	}
+	// e only needs to take 0 and the sizes of items no larger than half a bin
+	epsilons := []Size{0}
+	for _, size := range sizes {
+		if 2*size <= binSize {
+			epsilons = append(epsilons, size)
+		}
+	}
+
+	best := Count(0)
+	prefix := make([]Size, len(sizes)+1) // prefix[i] = mapped size of the items in sizes[:i]
+	for k := Size(1); k <= feketeSchepersK; k++ {
+		for i, size := range sizes {
+			mapped := (k + 1) * size / binSize * binSize // scaled by k, so a bin holds k*binSize
+			if (k+1)*size%binSize == 0 {
+				mapped = k * size
+			}
+			prefix[i+1] = prefix[i] + counts[size]*mapped
+		}
+		for _, epsilon := range epsilons {
+			low := sort.Search(len(sizes), func(i int) bool { return sizes[i] >= epsilon })
+			high := sort.Search(len(sizes), func(i int) bool { return sizes[i] > binSize-epsilon })
+			sum := prefix[high] - prefix[low] + countAbove[high]*k*binSize
+			if bound := Count(ceilDivide(sum, k*binSize)); bound > best {
+				best = bound
+			}
+		}
+	}
+	return best
+}
+
+// ceilDivide divide two non-negative sizes, rounding up
+func ceilDivide(a, b Size) Size {
+	return (a + b - 1) / b
 }
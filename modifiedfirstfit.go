@@ -1,5 +1,7 @@
 package binpacking
 
+import "context"
+
 // MFFDCategory type representing a category in MFFD
 type MFFDCategory int
 
@@ -31,8 +33,8 @@ const (
 	g
 )
 
-// PackAllMFFD pack all items for MFFD
-func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
+// PackAllMFFD pack all items for MFFD, checking the context between steps
+func (binCollection *BinCollectionImpl) PackAllMFFD(ctx context.Context, items Items) error {
 	aCount := 0
 	bItems := make(Items, 0)
 	cdeItems := make(Items, 0)
@@ -58,6 +60,10 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// STEP 2
 
 	// maintain a list of all A containers containing a B value
@@ -71,7 +77,7 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 			// if the bItem remains unpacked
 			if bItem > 0 {
 				// get the current A bin
-				bin := binCollection.GetBin(i)
+				bin := binCollection.binAt(i)
 				if bin.CanFit(bItem) {
 					// the unpacked item can fit, so pack it
 					bin.Pack(bItem)
@@ -83,11 +89,15 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 		}
 		// if we packed an item, set its value in bItems to -1 so we
 		// don't pack it twice
-		if bItemPacked > 0 {
+		if bItemPacked >= 0 {
 			bItems[bItemPacked] = -1
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// STEP 3
 
 	// iterate backwards through A containers
@@ -116,10 +126,11 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 		}
 
 		// get the current A bin
-		bin := binCollection.GetBin(i)
+		bin := binCollection.binAt(i)
 
-		// do nothing if the bin cannot fit the sum of the two smallest items in C D E
-		if !bin.CanFit(Item(cdeItems[twoSmallest[0]] + cdeItems[twoSmallest[1]])) {
+		// do nothing if the bin cannot fit the sum of the two smallest items in C D E,
+		// or has no room for two more items
+		if !bin.CanFit(Item(cdeItems[twoSmallest[0]]+cdeItems[twoSmallest[1]])) || !bin.hasSlots(2) {
 			continue
 		}
 
@@ -140,6 +151,10 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// STEP 4
 
 	unpackedCounter := 0
@@ -150,7 +165,7 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 		for i := 0; i < aCount; i++ {
 			unpackedCounter = 0 // reset to zero for each bin to get an accurate count
 			// get the current A bin
-			bin := binCollection.GetBin(i)
+			bin := binCollection.binAt(i)
 
 			// attempt to pack a B item
 			for j := 0; j < len(bItems); j++ {
@@ -196,6 +211,10 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 			break
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// STEP 5
 
 	// if we actually have remaining items
@@ -209,7 +228,7 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 			for i := aCount; i < int(binCollection.GetTotalBins()); i++ {
 				unpackedCounter = 0 // reset to zero for each bin to get an accurate count
 				// get the current bin
-				bin := binCollection.GetBin(i)
+				bin := binCollection.binAt(i)
 
 				// attempt to pack a B item
 				for j := 0; j < len(bItems); j++ {
@@ -262,5 +281,5 @@ func (binCollection *BinCollectionImpl) PackAllMFFD(items Items) {
 			}
 		}
 	}
-
+	return nil
 }
@@ -0,0 +1,237 @@
+package binpacking
+
+import (
+	"context"
+	"sort"
+)
+
+// PackAllMTP pack all items using Martello and Toth's MTP branch-and-bound.
+// The better of first fit decreasing and best fit decreasing is the initial
+// upper bound, and each node is bounded by applying the MTRP reduction to
+// the remaining problem followed by the L2 lower bound, while the stronger
+// L3 bound decides when the search can stop. When MaxItems is set, bins
+// with that many items take no more, and the bounds also count items.
+// If the context is done before the search finishes, the best packing
+// found so far is used and the status is set to NotProvenOptimal.
+func (binCollection *BinCollectionImpl) PackAllMTP(ctx context.Context, items Items) {
+	binCollection.solveMTP(ctx, items, nil, 0)
+}
+
+// solveMTP run the MTP search, also given a packing to start from as the
+// bin of each item, numbered from zero, and a lower bound, when known
+func (binCollection *BinCollectionImpl) solveMTP(ctx context.Context, items Items, incumbent []int, floor Count) {
+	search := &mtpSearch{
+		ctx:        ctx,
+		capacity:   binCollection.BinCapacity,
+		maxItems:   binCollection.MaxItems,
+		items:      items,
+		order:      items.decreasingOrder(),
+		assignment: make([]int, len(items)),
+		forced:     make([]bool, len(items))}
+	search.best = search.heuristicAssignment()
+	search.bestBins = countBins(search.best)
+	if incumbent != nil && countBins(incumbent) < search.bestBins {
+		search.best, search.bestBins = incumbent, countBins(incumbent)
+	}
+	search.floor = LowerBoundL3(items, binCollection.BinCapacity)
+	if bound := LowerBoundCardinality(items, binCollection.BinCapacity, binCollection.MaxItems); bound > search.floor {
+		search.floor = bound
+	}
+	if floor > search.floor {
+		search.floor = floor
+	}
+
+	binCollection.Status = Optimal
+	if search.bestBins > search.floor {
+		search.branch(0)
+		if search.interrupted {
+			binCollection.Status = NotProvenOptimal
+		}
+	}
+	binCollection.Nodes = search.nodes
+
+	for i := 0; i < int(search.bestBins); i++ {
+		binCollection.NewBin()
+	}
+	offset := int(binCollection.GetTotalBins() - search.bestBins)
+	for _, position := range search.order {
+		binCollection.binAt(offset+search.best[position]).PackIndex(items[position], position)
+	}
+}
+
+// mtpSearch state of a single MTP search
+type mtpSearch struct {
+	ctx         context.Context
+	capacity    Size
+	maxItems    Count // most items in a bin, unlimited when zero
+	items       Items
+	order       []int  // positions of the items, largest first
+	loads       []Size // usage of each bin in the current partial packing
+	counts      []int  // number of items in each bin in the current partial packing
+	assignment  []int  // bin of each item in the current partial packing
+	forced      []bool // whether each item was put in the bin it exactly fills without branching
+	best        []int  // bin of each item in the best packing found
+	bestBins    Count
+	floor       Count // lower bound for the whole problem
+	nodes       int64
+	interrupted bool // the context was done before the search finished
+}
+
+// heuristicAssignment the better packing of first fit decreasing and best
+// fit decreasing, as the bin of each item
+func (search *mtpSearch) heuristicAssignment() []int {
+	var best []int
+	for _, packer := range []Packer{firstFitPacker{decreasing: true}, bestFitPacker{decreasing: true}} {
+		heuristic := &BinCollectionImpl{BinCapacity: search.capacity, Bins: make(Bins, 0), MaxItems: search.maxItems}
+		packer.PackAll(context.Background(), heuristic, search.items)
+		if best != nil && countBins(best) <= heuristic.GetTotalBins() {
+			continue
+		}
+		best = make([]int, len(search.items))
+		for i, bin := range heuristic.Bins {
+			for _, position := range bin.Indices {
+				best[position] = i
+			}
+		}
+	}
+	return best
+}
+
+// branch assign the item at the given depth of the search order to each
+// bin it fits in, then to a new bin, pruning with the node's lower bound
+func (search *mtpSearch) branch(depth int) {
+	search.nodes++
+	if depth == len(search.order) {
+		search.bestBins = Count(len(search.loads))
+		search.best = make([]int, len(search.assignment))
+		copy(search.best, search.assignment)
+		return
+	}
+	if search.ctx.Err() != nil {
+		search.interrupted = true
+		return
+	}
+	if search.bound(depth) >= search.bestBins {
+		return
+	}
+
+	position := search.order[depth]
+	size := Size(search.items[position])
+	// an item which exactly fills a bin can always be put there, unless the
+	// number of items is limited, as the items it would take the place of
+	// may not all fit where it came from
+	for bin, load := range search.loads {
+		if load+size == search.capacity && search.maxItems == 0 {
+			search.forced[position] = true
+			search.assign(depth, position, bin)
+			search.forced[position] = false
+			return
+		}
+	}
+	// equal items are interchangeable, so an item never goes in an earlier
+	// bin than the previous item of the same size, unless that item was put
+	// where it exactly fills a bin without trying the bins before it
+	first := 0
+	if previous := depth - 1; previous >= 0 && search.items[search.order[previous]] == search.items[position] &&
+		!search.forced[search.order[previous]] {
+		first = search.assignment[search.order[previous]]
+	}
+	// try the fullest bins first, skipping bins with the same load and
+	// number of items as one already tried since they lead to equivalent
+	// subproblems
+	candidates := make([]int, 0, len(search.loads)-first)
+	tried := make(map[mtpBinState]bool)
+	for bin := first; bin < len(search.loads); bin++ {
+		state := mtpBinState{search.loads[bin], search.counts[bin]}
+		if search.maxItems == 0 {
+			state.count = 0 // the number of items makes no difference
+		}
+		if state.load+size <= search.capacity && search.hasSlot(bin) && !tried[state] {
+			tried[state] = true
+			candidates = append(candidates, bin)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return search.loads[candidates[i]] > search.loads[candidates[j]]
+	})
+	for _, bin := range candidates {
+		search.assign(depth, position, bin)
+		if search.bestBins <= search.floor {
+			return // the incumbent meets the lower bound, so it is optimal
+		}
+	}
+	if Count(len(search.loads))+1 < search.bestBins {
+		search.loads = append(search.loads, 0)
+		search.counts = append(search.counts, 0)
+		search.assign(depth, position, len(search.loads)-1)
+		search.loads = search.loads[:len(search.loads)-1]
+		search.counts = search.counts[:len(search.counts)-1]
+	}
+}
+
+// mtpBinState what matters about an open bin to the items left to pack
+type mtpBinState struct {
+	load  Size
+	count int
+}
+
+// hasSlot whether a bin can take another item without going over MaxItems
+func (search *mtpSearch) hasSlot(bin int) bool {
+	return search.maxItems == 0 || Count(search.counts[bin]) < search.maxItems
+}
+
+// assign put an item in a bin, search the rest of the tree, then take it out
+func (search *mtpSearch) assign(depth, position, bin int) {
+	search.loads[bin] += Size(search.items[position])
+	search.counts[bin]++
+	search.assignment[position] = bin
+	search.branch(depth + 1)
+	search.loads[bin] -= Size(search.items[position])
+	search.counts[bin]--
+}
+
+// bound lower bound on the bins needed to complete the current partial
+// packing. Each open bin is treated as a single item of its load, which
+// only relaxes the problem, and the result is reduced before applying L2.
+// When MaxItems is set, the items left which the open bins have no slots
+// for need a bin for every MaxItems of them.
+func (search *mtpSearch) bound(depth int) Count {
+	remaining := make(Items, 0, len(search.loads)+len(search.order)-depth)
+	for _, load := range search.loads {
+		if load > 0 {
+			remaining = append(remaining, Item(load))
+		}
+	}
+	for _, position := range search.order[depth:] {
+		remaining = append(remaining, search.items[position])
+	}
+	fixed, free := reduce(remaining, search.capacity)
+	rest := make(Items, len(free))
+	for i, position := range free {
+		rest[i] = remaining[position]
+	}
+	bound := Count(len(fixed)) + LowerBoundL2(rest, search.capacity)
+	if search.maxItems > 0 {
+		left := len(search.order) - depth
+		for bin := range search.loads {
+			left -= int(search.maxItems) - search.counts[bin]
+		}
+		if left > 0 {
+			if counted := Count(len(search.loads)) + Count(ceilDivide(Size(left), Size(search.maxItems))); counted > bound {
+				bound = counted
+			}
+		}
+	}
+	return bound
+}
+
+// countBins number of bins used by an assignment of items to bins
+func countBins(assignment []int) Count {
+	bins := 0
+	for _, bin := range assignment {
+		if bin+1 > bins {
+			bins = bin + 1
+		}
+	}
+	return Count(bins)
+}
@@ -10,3 +10,8 @@ func NextFitPack(binCollection *BinCollectionImpl, item Item) {
 		newBin.Pack(item)
 	}
 }
+
+// NextFitDecreasingPack pack the next item using the next fit decreasing algorithm
+func NextFitDecreasingPack(binCollection *BinCollectionImpl, item Item) {
+	NextFitPack(binCollection, item)
+}
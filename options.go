@@ -0,0 +1,46 @@
+package binpacking
+
+import (
+	"context"
+	"time"
+)
+
+// Options tuning parameters for the algorithms that accept them.
+// Zero values select each algorithm's default.
+type Options struct {
+	// K number of size classes for the Harmonic algorithms, which
+	// bounds how many bins Harmonic keeps open at a time
+	K int `json:"k,omitempty"`
+	// Reduce fix bins with the MTRP reduction before running the
+	// algorithm, which then only packs the items left over. Not used
+	// when items conflict or bins hold a limited number of items,
+	// which the reduction does not take into account
+	Reduce bool `json:"reduce,omitempty"`
+	// Seed seed for the random choices of the metaheuristics,
+	// which give the same packing every time for the same seed
+	Seed int64 `json:"seed,omitempty"`
+	// Iterations the most moves simulated annealing or tabu search try
+	Iterations int `json:"iterations,omitempty"`
+	// Generations the most generations the genetic algorithm runs for
+	Generations int `json:"generations,omitempty"`
+	// Population the number of packings the genetic algorithm evolves
+	Population int `json:"population,omitempty"`
+	// TimeLimit the longest the metaheuristics, column generation, the
+	// variable-sized search and the cutting stock solvers search for,
+	// unlimited when zero
+	TimeLimit time.Duration `json:"timeLimit,omitempty"`
+	// RepackBudget the most items a Session may move between bins after
+	// each insertion or removal to empty bins, none when zero
+	RepackBudget int `json:"repackBudget,omitempty"`
+	// OnImprovement called by the metaheuristics with the bin count
+	// of their starting packing and of each better packing found
+	OnImprovement func(bins Count) `json:"-"`
+}
+
+// withTimeLimit a context which is also done once the time limit has passed
+func (options Options) withTimeLimit(ctx context.Context) (context.Context, context.CancelFunc) {
+	if options.TimeLimit > 0 {
+		return context.WithTimeout(ctx, options.TimeLimit)
+	}
+	return context.WithCancel(ctx)
+}
@@ -7,10 +7,18 @@ type PackingList struct {
 	// ItemCount the number of items being passed in
 	Count `json:"count"`
 	// Algorithm the algorithm being used to solve the problem
-	Algorithm `json:"algorithm"`
+	Algorithm Algorithm `json:"algorithm"`
 	// Items the actual items being passed in
 	Items       `json:"items"`
 	Variability `json:"variability"`
 	Center      int   `json:"center"`
 	LowerBound  Count `json:"lowerBound"`
+	// BinTypes the kinds of bin to choose from, if not all bins are of size Size
+	BinTypes BinTypes `json:"binTypes,omitempty"`
+	// Conflicts pairs of items, by their positions in Items, which may not share a bin
+	Conflicts Conflicts `json:"conflicts,omitempty"`
+	// MaxItems the most items any bin may hold, unlimited when zero
+	MaxItems Count `json:"maxItems,omitempty"`
+	// Options parameters passed on to the algorithm
+	Options
 }
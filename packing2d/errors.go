@@ -0,0 +1,50 @@
+package packing2d
+
+import (
+	"fmt"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// UnknownAlgorithmError returned when the package has no such algorithm
+type UnknownAlgorithmError struct {
+	Algorithm Algorithm
+}
+
+func (err *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unsupported algorithm: %v", err.Algorithm)
+}
+
+// InvalidSheetError returned when sheets have a non-positive width or height
+type InvalidSheetError struct {
+	Width, Height binpacking.Size
+}
+
+func (err *InvalidSheetError) Error() string {
+	return fmt.Sprintf("invalid sheet size: %vx%v", err.Width, err.Height)
+}
+
+// InvalidRectangleError returned when a rectangle has a non-positive width or height
+type InvalidRectangleError struct {
+	// Index position of the rectangle in the input
+	Index     int
+	Rectangle Rectangle
+}
+
+func (err *InvalidRectangleError) Error() string {
+	return fmt.Sprintf("invalid size for rectangle %v: %vx%v", err.Index, err.Rectangle.Width, err.Rectangle.Height)
+}
+
+// OversizeRectangleError returned when a rectangle does not fit on
+// a sheet, also when turned if rotation is allowed
+type OversizeRectangleError struct {
+	// Index position of the rectangle in the input
+	Index         int
+	Rectangle     Rectangle
+	Width, Height binpacking.Size
+}
+
+func (err *OversizeRectangleError) Error() string {
+	return fmt.Sprintf("rectangle %v of size %vx%v does not fit a %vx%v sheet",
+		err.Index, err.Rectangle.Width, err.Rectangle.Height, err.Width, err.Height)
+}
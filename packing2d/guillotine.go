@@ -0,0 +1,61 @@
+package packing2d
+
+import "github.com/gnboorse/binpacking"
+
+// guillotine the free space of a sheet as disjoint rectangles, each
+// left by cutting a free rectangle from edge to edge around a placed one
+type guillotine struct {
+	free []freeRectangle
+}
+
+// newGuillotine the free space of an empty sheet
+func newGuillotine(width, height binpacking.Size) sheetPacker {
+	return &guillotine{free: []freeRectangle{{0, 0, width, height}}}
+}
+
+// find the free rectangle with the least area over when the rectangle is put
+// in its corner, then the shortest side over (best area fit)
+func (packer *guillotine) find(width, height binpacking.Size, rotate bool) (candidate, bool) {
+	var best candidate
+	found := false
+	for i, free := range packer.free {
+		for _, size := range orientations(width, height, rotate) {
+			if size[0] > free.width || size[1] > free.height {
+				continue
+			}
+			short := free.width - size[0]
+			if free.height-size[1] < short {
+				short = free.height - size[1]
+			}
+			placed := candidate{x: free.x, y: free.y, width: size[0], height: size[1], rotated: size[0] != width,
+				primary: free.width*free.height - size[0]*size[1], secondary: short, node: i}
+			if !found || placed.better(best) {
+				best, found = placed, true
+			}
+		}
+	}
+	return best, found
+}
+
+// place cut the free rectangle used in two along the shorter leftover axis,
+// so the larger leftover piece spans the whole free rectangle
+func (packer *guillotine) place(found candidate) {
+	free := packer.free[found.node]
+	packer.free = append(packer.free[:found.node], packer.free[found.node+1:]...)
+	rightWidth, topHeight := free.width-found.width, free.height-found.height
+	var right, top freeRectangle
+	if rightWidth <= topHeight {
+		// cut across: the piece above spans the full width
+		right = freeRectangle{free.x + found.width, free.y, rightWidth, found.height}
+		top = freeRectangle{free.x, free.y + found.height, free.width, topHeight}
+	} else {
+		// cut down: the piece to the right spans the full height
+		right = freeRectangle{free.x + found.width, free.y, rightWidth, free.height}
+		top = freeRectangle{free.x, free.y + found.height, found.width, topHeight}
+	}
+	for _, piece := range []freeRectangle{right, top} {
+		if piece.width > 0 && piece.height > 0 {
+			packer.free = append(packer.free, piece)
+		}
+	}
+}
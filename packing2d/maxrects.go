@@ -0,0 +1,79 @@
+package packing2d
+
+import "github.com/gnboorse/binpacking"
+
+// maxRects the free space of a sheet as every maximal empty rectangle,
+// which overlap one another, as in Jylänki's MaxRects
+type maxRects struct {
+	free []freeRectangle
+}
+
+// newMaxRects the free space of an empty sheet
+func newMaxRects(width, height binpacking.Size) sheetPacker {
+	return &maxRects{free: []freeRectangle{{0, 0, width, height}}}
+}
+
+// find the free rectangle leaving the shortest side over when the rectangle
+// is put in its corner, then the shortest longer side (best short side fit)
+func (packer *maxRects) find(width, height binpacking.Size, rotate bool) (candidate, bool) {
+	var best candidate
+	found := false
+	for i, free := range packer.free {
+		for _, size := range orientations(width, height, rotate) {
+			if size[0] > free.width || size[1] > free.height {
+				continue
+			}
+			short, long := free.width-size[0], free.height-size[1]
+			if short > long {
+				short, long = long, short
+			}
+			placed := candidate{x: free.x, y: free.y, width: size[0], height: size[1],
+				rotated: size[0] != width, primary: short, secondary: long, node: i}
+			if !found || placed.better(best) {
+				best, found = placed, true
+			}
+		}
+	}
+	return best, found
+}
+
+// place split every free rectangle the placed one overlaps into the parts
+// of it left on each side, then drop those inside another
+func (packer *maxRects) place(found candidate) {
+	used := freeRectangle{found.x, found.y, found.width, found.height}
+	split := make([]freeRectangle, 0, len(packer.free)+4)
+	for _, free := range packer.free {
+		if used.x >= free.x+free.width || used.x+used.width <= free.x ||
+			used.y >= free.y+free.height || used.y+used.height <= free.y {
+			split = append(split, free)
+			continue
+		}
+		if used.x > free.x {
+			split = append(split, freeRectangle{free.x, free.y, used.x - free.x, free.height})
+		}
+		if right := used.x + used.width; right < free.x+free.width {
+			split = append(split, freeRectangle{right, free.y, free.x + free.width - right, free.height})
+		}
+		if used.y > free.y {
+			split = append(split, freeRectangle{free.x, free.y, free.width, used.y - free.y})
+		}
+		if top := used.y + used.height; top < free.y+free.height {
+			split = append(split, freeRectangle{free.x, top, free.width, free.y + free.height - top})
+		}
+	}
+
+	packer.free = packer.free[:0]
+	for i, free := range split {
+		contained := false
+		for j, other := range split {
+			// of two equal rectangles, only the first is kept
+			if i != j && other.contains(free) && (!free.contains(other) || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			packer.free = append(packer.free, free)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Package packing2d packs rectangles into fixed-size sheets, following the
+// conventions of the binpacking package: a PackingList names the algorithm
+// and items, and the solution lists each sheet with the position of every
+// rectangle placed on it.
+package packing2d
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// Algorithm types of rectangle packing algorithms supported by the package
+type Algorithm int
+
+const (
+	// Unknown for default value initialization
+	Unknown Algorithm = iota
+	// MaxRects keeps every maximal free rectangle of a sheet, placing
+	// each rectangle where it leaves the shortest side free
+	MaxRects
+	// Skyline keeps the top edge of the placed rectangles, placing each
+	// rectangle as low as possible, then as far left as possible
+	Skyline
+	// Guillotine splits the free space with edge to edge cuts, placing each
+	// rectangle in the free rectangle it fills most of, as sheet cutters need
+	Guillotine
+)
+
+var names = []string{
+	"Unknown",
+	"MaxRects",
+	"Skyline",
+	"Guillotine"}
+
+// newPackers create the state of an empty sheet for each algorithm
+var newPackers = []func(width, height binpacking.Size) sheetPacker{
+	nil,
+	newMaxRects,
+	newSkyline,
+	newGuillotine}
+
+// Algorithms get the names of every algorithm
+func Algorithms() []string {
+	return append([]string{}, names[1:]...)
+}
+
+func (algorithm Algorithm) String() string {
+	if algorithm < 0 || int(algorithm) >= len(names) {
+		return names[Unknown]
+	}
+	return names[algorithm]
+}
+
+// MarshalJSON algorithms are written by name
+func (algorithm Algorithm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algorithm.String())
+}
+
+// UnmarshalJSON read an algorithm from either its name or its number
+func (algorithm *Algorithm) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*algorithm = GetAlgorithm(name)
+		return nil
+	}
+	var number int
+	if err := json.Unmarshal(b, &number); err != nil {
+		return err
+	}
+	*algorithm = Algorithm(number)
+	return nil
+}
+
+// GetAlgorithm get an algorithm from string, Unknown if there is none
+func GetAlgorithm(s string) Algorithm {
+	for i, name := range names {
+		if name == s {
+			return Algorithm(i)
+		}
+	}
+	return Unknown
+}
+
+// Rectangle an item to be packed
+type Rectangle struct {
+	Width  binpacking.Size `json:"width"`
+	Height binpacking.Size `json:"height"`
+}
+
+// Area the area of the rectangle
+func (rectangle Rectangle) Area() binpacking.Size {
+	return rectangle.Width * rectangle.Height
+}
+
+// Placement where a rectangle was put on its sheet, with its lower left
+// corner at X, Y. Width and Height are as placed, so they are swapped
+// from the input when the rectangle was rotated.
+type Placement struct {
+	// Index the position of the rectangle in the input passed to PackAll
+	Index   int             `json:"index"`
+	X       binpacking.Size `json:"x"`
+	Y       binpacking.Size `json:"y"`
+	Width   binpacking.Size `json:"width"`
+	Height  binpacking.Size `json:"height"`
+	Rotated bool            `json:"rotated,omitempty"`
+}
+
+// Sheet a sheet holding placed rectangles
+type Sheet struct {
+	Width      binpacking.Size `json:"width"`
+	Height     binpacking.Size `json:"height"`
+	Placements []Placement     `json:"placements"`
+	// Usage the total area of the rectangles on the sheet
+	Usage  binpacking.Size `json:"usage"`
+	packer sheetPacker
+}
+
+// PackingList input for a rectangle packing problem
+type PackingList struct {
+	// Width the width of the sheets being packed
+	Width binpacking.Size `json:"width"`
+	// Height the height of the sheets being packed
+	Height binpacking.Size `json:"height"`
+	// Algorithm the algorithm being used to solve the problem
+	Algorithm Algorithm `json:"algorithm"`
+	// Items the actual rectangles being passed in
+	Items []Rectangle `json:"items"`
+	// AllowRotation whether rectangles may be turned by 90 degrees
+	AllowRotation bool `json:"allowRotation,omitempty"`
+}
+
+// SheetCollection an instance of the rectangle packing problem
+type SheetCollection struct {
+	Width         binpacking.Size   `json:"width"`
+	Height        binpacking.Size   `json:"height"`
+	TotalSheets   binpacking.Count  `json:"count"`
+	Sheets        []Sheet           `json:"sheets"`
+	Algorithm     Algorithm         `json:"algorithm"`
+	AllowRotation bool              `json:"allowRotation,omitempty"`
+	Status        binpacking.Status `json:"status"`
+	SolutionTime  int64             `json:"solution_time"`
+}
+
+// NewSheetCollection create an instance of the rectangle packing problem from a PackingList object
+func NewSheetCollection(list *PackingList) *SheetCollection {
+	return &SheetCollection{
+		Width:         list.Width,
+		Height:        list.Height,
+		Sheets:        make([]Sheet, 0),
+		Algorithm:     list.Algorithm,
+		AllowRotation: list.AllowRotation}
+}
+
+// PackAll pack all of the given rectangles
+func (collection *SheetCollection) PackAll(items []Rectangle) error {
+	return collection.PackAllContext(context.Background(), items)
+}
+
+// PackAllContext pack all of the given rectangles, largest area first,
+// giving up when the context is done. Each rectangle goes where the
+// algorithm scores best on any sheet already open, or on a new sheet
+// if it fits none of them.
+func (collection *SheetCollection) PackAllContext(ctx context.Context, items []Rectangle) error {
+	if collection.Algorithm <= Unknown || int(collection.Algorithm) >= len(names) {
+		return &UnknownAlgorithmError{Algorithm: collection.Algorithm}
+	}
+	if collection.Width <= 0 || collection.Height <= 0 {
+		return &InvalidSheetError{Width: collection.Width, Height: collection.Height}
+	}
+	for i, item := range items {
+		if err := collection.validate(i, item); err != nil {
+			return err
+		}
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		first, second := items[order[a]], items[order[b]]
+		if first.Area() != second.Area() {
+			return first.Area() > second.Area()
+		}
+		return longerSide(first) > longerSide(second)
+	})
+
+	collection.Sheets = make([]Sheet, 0)
+	collection.Status = binpacking.Unsolved
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		collection.place(items[position], position)
+	}
+	collection.TotalSheets = binpacking.Count(len(collection.Sheets))
+	collection.Status = binpacking.Feasible
+	return nil
+}
+
+// place put a rectangle where it scores best on any open sheet, or on a new sheet
+func (collection *SheetCollection) place(item Rectangle, position int) {
+	chosen := -1
+	var best candidate
+	for s := range collection.Sheets {
+		found, ok := collection.Sheets[s].packer.find(item.Width, item.Height, collection.AllowRotation)
+		if ok && (chosen < 0 || found.better(best)) {
+			chosen, best = s, found
+		}
+	}
+	if chosen < 0 {
+		collection.Sheets = append(collection.Sheets, Sheet{
+			Width:      collection.Width,
+			Height:     collection.Height,
+			Placements: make([]Placement, 0),
+			packer:     newPackers[collection.Algorithm](collection.Width, collection.Height)})
+		chosen = len(collection.Sheets) - 1
+		// validated to fit an empty sheet, rotated if need be
+		best, _ = collection.Sheets[chosen].packer.find(item.Width, item.Height, collection.AllowRotation)
+	}
+	sheet := &collection.Sheets[chosen]
+	sheet.packer.place(best)
+	sheet.Placements = append(sheet.Placements, Placement{
+		Index:   position,
+		X:       best.x,
+		Y:       best.y,
+		Width:   best.width,
+		Height:  best.height,
+		Rotated: best.rotated})
+	sheet.Usage += item.Area()
+}
+
+// validate check that a rectangle fits on an empty sheet
+func (collection *SheetCollection) validate(index int, item Rectangle) error {
+	if item.Width <= 0 || item.Height <= 0 {
+		return &InvalidRectangleError{Index: index, Rectangle: item}
+	}
+	fits := item.Width <= collection.Width && item.Height <= collection.Height
+	if collection.AllowRotation {
+		fits = fits || (item.Height <= collection.Width && item.Width <= collection.Height)
+	}
+	if !fits {
+		return &OversizeRectangleError{Index: index, Rectangle: item, Width: collection.Width, Height: collection.Height}
+	}
+	return nil
+}
+
+// GetTotalBins getter for the number of sheets used
+func (collection *SheetCollection) GetTotalBins() binpacking.Count {
+	return collection.TotalSheets
+}
+
+// String get the JSON representation of the solution
+func (collection *SheetCollection) String() string {
+	jsonString, _ := json.MarshalIndent(collection, "", "  ")
+	return string(jsonString)
+}
+
+// SetTime set the execution time for a single run
+func (collection *SheetCollection) SetTime(nanoseconds int64) {
+	collection.SolutionTime = nanoseconds
+}
+
+// longerSide the longer of the rectangle's sides
+func longerSide(rectangle Rectangle) binpacking.Size {
+	if rectangle.Width > rectangle.Height {
+		return rectangle.Width
+	}
+	return rectangle.Height
+}
+
+// sheetPacker the free space of one sheet, as an algorithm tracks it
+type sheetPacker interface {
+	// find the best place for a rectangle, also turned by 90 degrees
+	// if rotate is set, returning false if it fits nowhere
+	find(width, height binpacking.Size, rotate bool) (candidate, bool)
+	// place a rectangle where find put it
+	place(found candidate)
+}
+
+// candidate a place for a rectangle, scored so lower is better
+type candidate struct {
+	x, y, width, height binpacking.Size
+	rotated             bool
+	primary, secondary  binpacking.Size
+	node                int // the free rectangle or skyline segment it uses
+}
+
+// better whether the candidate scores better than another
+func (found candidate) better(other candidate) bool {
+	return found.primary < other.primary || (found.primary == other.primary && found.secondary < other.secondary)
+}
+
+// orientations the ways a rectangle can be placed, as width and height
+func orientations(width, height binpacking.Size, rotate bool) [][2]binpacking.Size {
+	if rotate && width != height {
+		return [][2]binpacking.Size{{width, height}, {height, width}}
+	}
+	return [][2]binpacking.Size{{width, height}}
+}
+
+// freeRectangle an empty area of a sheet
+type freeRectangle struct {
+	x, y, width, height binpacking.Size
+}
+
+// contains whether the free rectangle holds another entirely
+func (free freeRectangle) contains(other freeRectangle) bool {
+	return other.x >= free.x && other.y >= free.y &&
+		other.x+other.width <= free.x+free.width && other.y+other.height <= free.y+free.height
+}
@@ -0,0 +1,90 @@
+package packing2d
+
+import "github.com/gnboorse/binpacking"
+
+// skylineSegment a stretch of the top edge of the placed rectangles
+type skylineSegment struct {
+	x, y, width binpacking.Size
+}
+
+// skyline the free space of a sheet as the top edge of the rectangles
+// placed, left to right. Space below the edge is given up for lost.
+type skyline struct {
+	width, height binpacking.Size
+	segments      []skylineSegment
+}
+
+// newSkyline the free space of an empty sheet
+func newSkyline(width, height binpacking.Size) sheetPacker {
+	return &skyline{width: width, height: height, segments: []skylineSegment{{0, 0, width}}}
+}
+
+// find the lowest place for the top of the rectangle with its left edge at
+// the start of a segment, then the leftmost (bottom left)
+func (packer *skyline) find(width, height binpacking.Size, rotate bool) (candidate, bool) {
+	var best candidate
+	found := false
+	for i, segment := range packer.segments {
+		for _, size := range orientations(width, height, rotate) {
+			y, ok := packer.rest(i, size[0])
+			if !ok || y+size[1] > packer.height {
+				continue
+			}
+			placed := candidate{x: segment.x, y: y, width: size[0], height: size[1],
+				rotated: size[0] != width, primary: y + size[1], secondary: segment.x, node: i}
+			if !found || placed.better(best) {
+				best, found = placed, true
+			}
+		}
+	}
+	return best, found
+}
+
+// rest the height a rectangle of the given width rests at with its left edge
+// at the start of a segment: the highest segment under it. Returns false if
+// it would stick out of the right side.
+func (packer *skyline) rest(index int, width binpacking.Size) (binpacking.Size, bool) {
+	left := packer.segments[index].x
+	if left+width > packer.width {
+		return 0, false
+	}
+	var y binpacking.Size
+	for _, segment := range packer.segments[index:] {
+		if segment.x >= left+width {
+			break
+		}
+		if segment.y > y {
+			y = segment.y
+		}
+	}
+	return y, true
+}
+
+// place raise the skyline over the rectangle, cutting back the segments
+// it covers and merging neighbours of the same height
+func (packer *skyline) place(found candidate) {
+	right := found.x + found.width
+	segments := make([]skylineSegment, 0, len(packer.segments)+2)
+	segments = append(segments, packer.segments[:found.node]...)
+	segments = append(segments, skylineSegment{found.x, found.y + found.height, found.width})
+	for _, segment := range packer.segments[found.node:] {
+		end := segment.x + segment.width
+		if end <= right {
+			continue // covered entirely
+		}
+		if segment.x < right {
+			segment = skylineSegment{right, segment.y, end - right}
+		}
+		segments = append(segments, segment)
+	}
+
+	packer.segments = segments[:1]
+	for _, segment := range segments[1:] {
+		last := &packer.segments[len(packer.segments)-1]
+		if last.y == segment.y {
+			last.width += segment.width
+		} else {
+			packer.segments = append(packer.segments, segment)
+		}
+	}
+}
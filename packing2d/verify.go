@@ -0,0 +1,74 @@
+package packing2d
+
+import "github.com/gnboorse/binpacking"
+
+// Verify check that a solution places each rectangle of the packing list
+// once, with its own size, turned only if rotation is allowed, that no
+// placement leaves its sheet or overlaps another, and that the solution's
+// bookkeeping is consistent. Sheets whose placements leave them or overlap
+// are reported as overfull, and placements which do not match the input
+// as index mismatches.
+func Verify(list *PackingList, sol *SheetCollection) *binpacking.VerificationReport {
+	report := &binpacking.VerificationReport{
+		TotalBins:  sol.TotalSheets,
+		ActualBins: binpacking.Count(len(sol.Sheets))}
+	used := make([]bool, len(list.Items))
+	for i, sheet := range sol.Sheets {
+		valid, overfull := true, false
+		var area binpacking.Size
+		for j, placement := range sheet.Placements {
+			area += placement.Width * placement.Height
+			if placement.X < 0 || placement.Y < 0 ||
+				placement.X+placement.Width > list.Width || placement.Y+placement.Height > list.Height {
+				overfull = true
+			}
+			for _, other := range sheet.Placements[:j] {
+				if placement.X < other.X+other.Width && other.X < placement.X+placement.Width &&
+					placement.Y < other.Y+other.Height && other.Y < placement.Y+placement.Height {
+					overfull = true
+				}
+			}
+			index := placement.Index
+			if index < 0 || index >= len(list.Items) || used[index] || !matches(list, placement) {
+				valid = false
+				continue
+			}
+			used[index] = true
+		}
+		if !valid {
+			report.IndexMismatches = append(report.IndexMismatches, i)
+		}
+		if overfull {
+			report.OverfullBins = append(report.OverfullBins, i)
+		}
+		if sheet.Width != list.Width || sheet.Height != list.Height {
+			report.CapacityMismatches = append(report.CapacityMismatches, i)
+		}
+		if area != sheet.Usage {
+			report.UsageMismatches = append(report.UsageMismatches, i)
+		}
+	}
+	for index, placed := range used {
+		if !placed {
+			report.MissingIndices = append(report.MissingIndices, index)
+		}
+	}
+
+	report.Valid = len(report.MissingIndices) == 0 &&
+		len(report.OverfullBins) == 0 &&
+		len(report.CapacityMismatches) == 0 &&
+		len(report.UsageMismatches) == 0 &&
+		len(report.IndexMismatches) == 0 &&
+		report.TotalBins == report.ActualBins
+	return report
+}
+
+// matches whether a placement has the size of the input rectangle it records,
+// turned only if it says so and rotation is allowed
+func matches(list *PackingList, placement Placement) bool {
+	item := list.Items[placement.Index]
+	if placement.Rotated {
+		return list.AllowRotation && placement.Width == item.Height && placement.Height == item.Width
+	}
+	return placement.Width == item.Width && placement.Height == item.Height
+}
@@ -0,0 +1,188 @@
+package packing3d
+
+import (
+	"sort"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// supportTolerance slack allowed when comparing the supported fraction of a base
+const supportTolerance = 1e-9
+
+// cuboid the space a loaded box takes: its lower rear left corner
+// and its size along the width, depth and height
+type cuboid struct {
+	corner, size [3]binpacking.Size
+}
+
+// end the far side of the cuboid along an axis
+func (space cuboid) end(axis int) binpacking.Size {
+	return space.corner[axis] + space.size[axis]
+}
+
+// overlaps whether two cuboids share any volume
+func (space cuboid) overlaps(other cuboid) bool {
+	for axis := 0; axis < 3; axis++ {
+		if space.corner[axis] >= other.end(axis) || other.corner[axis] >= space.end(axis) {
+			return false
+		}
+	}
+	return true
+}
+
+// covers whether the cuboid spans a point along every axis but one
+func (space cuboid) covers(point [3]binpacking.Size, except int) bool {
+	for axis := 0; axis < 3; axis++ {
+		if axis != except && (point[axis] < space.corner[axis] || point[axis] >= space.end(axis)) {
+			return false
+		}
+	}
+	return true
+}
+
+// loader a container being loaded, with the corners boxes may be put at
+type loader struct {
+	load    Load
+	support float64
+	placed  []cuboid
+	points  [][3]binpacking.Size
+}
+
+// newLoader an empty container with its floor's rear left corner free
+func newLoader(collection *ContainerCollection) *loader {
+	return &loader{
+		load:    Load{Container: collection.Container, Placements: make([]Placement, 0)},
+		support: collection.Support,
+		points:  [][3]binpacking.Size{{0, 0, 0}}}
+}
+
+// fits whether a box of the given weight can take the space: inside the
+// container, clear of every box loaded, within the weight limit, and resting
+// on enough of the boxes below
+func (loader *loader) fits(space cuboid, weight binpacking.Size) bool {
+	if !fitsWithin([3]binpacking.Size{space.end(0), space.end(1), space.end(2)}, loader.load.dimensions()) {
+		return false
+	}
+	if loader.load.MaxWeight > 0 && loader.load.Weight+weight > loader.load.MaxWeight {
+		return false
+	}
+	for _, other := range loader.placed {
+		if space.overlaps(other) {
+			return false
+		}
+	}
+	return supported(space, loader.placed, loader.support)
+}
+
+// supported whether at least the given fraction of the cuboid's base rests on
+// the tops of the others, or it stands on the floor
+func supported(space cuboid, others []cuboid, fraction float64) bool {
+	if fraction <= 0 || space.corner[2] == 0 {
+		return true
+	}
+	var area binpacking.Size
+	for _, other := range others {
+		if other.end(2) != space.corner[2] {
+			continue
+		}
+		width := minSize(space.end(0), other.end(0)) - maxSize(space.corner[0], other.corner[0])
+		depth := minSize(space.end(1), other.end(1)) - maxSize(space.corner[1], other.corner[1])
+		if width > 0 && depth > 0 {
+			area += width * depth
+		}
+	}
+	return float64(area) >= fraction*float64(space.size[0]*space.size[1])-supportTolerance
+}
+
+// place load a box into the space, then replace the corners it covers with
+// those it makes: its far corner along each of the given axes, and that
+// corner pushed back along each other given axis until it meets a box or
+// the container's side, so boxes put there lie flush against others
+func (loader *loader) place(item Box, index int, orientation Orientation, space cuboid, axes []int) {
+	loader.placed = append(loader.placed, space)
+	loader.load.Placements = append(loader.load.Placements, Placement{
+		Index:       index,
+		X:           space.corner[0],
+		Y:           space.corner[1],
+		Z:           space.corner[2],
+		Width:       space.size[0],
+		Depth:       space.size[1],
+		Height:      space.size[2],
+		Orientation: orientation})
+	loader.load.Usage += item.Volume()
+	loader.load.Weight += item.Weight
+
+	points := loader.points
+	for _, axis := range axes {
+		corner := space.corner
+		corner[axis] = space.end(axis)
+		points = append(points, corner)
+		for _, other := range axes {
+			if other != axis {
+				points = append(points, loader.project(corner, other))
+			}
+		}
+	}
+	loader.points = loader.points[:0:0]
+	seen := make(map[[3]binpacking.Size]bool)
+	for _, point := range points {
+		if !seen[point] && loader.open(point) {
+			seen[point] = true
+			loader.points = append(loader.points, point)
+		}
+	}
+	sort.Slice(loader.points, func(a, b int) bool {
+		first, second := loader.points[a], loader.points[b]
+		for axis := 2; axis >= 0; axis-- {
+			if first[axis] != second[axis] {
+				return first[axis] < second[axis]
+			}
+		}
+		return false
+	})
+}
+
+// project move a point back along an axis until it meets the
+// far side of a box, or the container's side
+func (loader *loader) project(point [3]binpacking.Size, axis int) [3]binpacking.Size {
+	var stop binpacking.Size
+	for _, other := range loader.placed {
+		if other.covers(point, axis) && other.end(axis) <= point[axis] && other.end(axis) > stop {
+			stop = other.end(axis)
+		}
+	}
+	point[axis] = stop
+	return point
+}
+
+// open whether a point is inside the container and not inside any box
+func (loader *loader) open(point [3]binpacking.Size) bool {
+	dimensions := loader.load.dimensions()
+	for axis := 0; axis < 3; axis++ {
+		if point[axis] >= dimensions[axis] {
+			return false
+		}
+	}
+	for _, other := range loader.placed {
+		if other.covers(point, -1) {
+			return false
+		}
+	}
+	return true
+}
+
+// minSize the smaller of two sizes
+func minSize(a, b binpacking.Size) binpacking.Size {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxSize the larger of two sizes
+func maxSize(a, b binpacking.Size) binpacking.Size {
+	if a > b {
+		return a
+	}
+	return b
+}
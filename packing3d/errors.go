@@ -0,0 +1,47 @@
+package packing3d
+
+import "fmt"
+
+// UnknownAlgorithmError returned when the package has no such algorithm
+type UnknownAlgorithmError struct {
+	Algorithm Algorithm
+}
+
+func (err *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unsupported algorithm: %v", err.Algorithm)
+}
+
+// InvalidContainerError returned when containers have a non-positive
+// size on some side, or a negative weight limit
+type InvalidContainerError struct {
+	Container Container
+}
+
+func (err *InvalidContainerError) Error() string {
+	return fmt.Sprintf("invalid container: %+v", err.Container)
+}
+
+// InvalidBoxError returned when a box has a non-positive size on
+// some side, a negative weight, or an unknown orientation
+type InvalidBoxError struct {
+	// Index position of the box in the input
+	Index int
+	Box   Box
+}
+
+func (err *InvalidBoxError) Error() string {
+	return fmt.Sprintf("invalid box %v: %+v", err.Index, err.Box)
+}
+
+// OversizeBoxError returned when a box does not fit an empty container
+// in any orientation it allows, or is heavier than a container holds
+type OversizeBoxError struct {
+	// Index position of the box in the input
+	Index     int
+	Box       Box
+	Container Container
+}
+
+func (err *OversizeBoxError) Error() string {
+	return fmt.Sprintf("box %v (%+v) does not fit container %+v", err.Index, err.Box, err.Container)
+}
@@ -0,0 +1,51 @@
+package packing3d
+
+import "context"
+
+// packExtremePoints load each box at the first corner of the first
+// container where it fits in an orientation it allows, trying the corners
+// lowest first, then rearmost, then leftmost, and opening a new container
+// when it fits in none
+func (collection *ContainerCollection) packExtremePoints(ctx context.Context, items []Box, order []int) error {
+	loaders := make([]*loader, 0)
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		placed := false
+		for _, loader := range loaders {
+			if placed = loader.placeAtPoint(items[position], position); placed {
+				break
+			}
+		}
+		if !placed {
+			loader := newLoader(collection)
+			loaders = append(loaders, loader)
+			loader.placeAtPoint(items[position], position) // validated to fit an empty container
+		}
+	}
+	collection.setLoads(loaders)
+	return nil
+}
+
+// placeAtPoint load a box at the first corner it fits, returning false if it fits none
+func (loader *loader) placeAtPoint(item Box, index int) bool {
+	for _, point := range loader.points {
+		for _, orientation := range item.allowed() {
+			space := cuboid{point, item.dimensions(orientation)}
+			if loader.fits(space, item.Weight) {
+				loader.place(item, index, orientation, space, []int{0, 1, 2})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setLoads replace the collection's loads with those of the loaders
+func (collection *ContainerCollection) setLoads(loaders []*loader) {
+	collection.Loads = make([]Load, len(loaders))
+	for i, loader := range loaders {
+		collection.Loads[i] = loader.load
+	}
+}
@@ -0,0 +1,97 @@
+package packing3d
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// packLayers fill one container at a time in horizontal layers. The first
+// box left which fits starts a layer, stood as flat as it allows, and sets
+// its height. Each box left after it which fits under that height is stood
+// as tall as fits, filling the layer, at the first corner of the layer's
+// floor where it fits, rearmost then leftmost, dropping onto the boxes
+// below where they leave a gap. Once no box left fits in a layer, the next
+// starts on top of it, and once none fits in a new layer, the next
+// container is opened.
+func (collection *ContainerCollection) packLayers(ctx context.Context, items []Box, order []int) error {
+	loaders := make([]*loader, 0)
+	for remaining := order; len(remaining) > 0; {
+		loader := newLoader(collection)
+		loaders = append(loaders, loader)
+		var base binpacking.Size
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			height, left := loader.fillLayer(items, remaining, base)
+			if height == 0 {
+				break
+			}
+			remaining, base = left, base+height
+		}
+		if len(loader.load.Placements) == 0 {
+			return binpacking.ErrInfeasible // every box left was validated to fit, so this cannot happen
+		}
+	}
+	collection.setLoads(loaders)
+	return nil
+}
+
+// fillLayer load the boxes which fit into a layer starting at the given
+// height, returning the layer's height, zero if no box fits, and the boxes left
+func (loader *loader) fillLayer(items []Box, remaining []int, base binpacking.Size) (binpacking.Size, []int) {
+	loader.points = [][3]binpacking.Size{{0, 0, base}}
+	var height binpacking.Size
+	left := make([]int, 0, len(remaining))
+	for _, position := range remaining {
+		item := items[position]
+		room := loader.load.Height - base
+		if height > 0 {
+			room = height
+		}
+		orientations := make([]Orientation, 0, len(allOrientations))
+		for _, orientation := range item.allowed() {
+			if item.dimensions(orientation)[2] <= room {
+				orientations = append(orientations, orientation)
+			}
+		}
+		sort.SliceStable(orientations, func(a, b int) bool {
+			first, second := item.dimensions(orientations[a])[2], item.dimensions(orientations[b])[2]
+			if height == 0 {
+				return first < second // flattest first, to start a low layer
+			}
+			return first > second // tallest first, to fill the layer
+		})
+		if !loader.placeInLayer(item, position, orientations, height == 0) {
+			left = append(left, position)
+		} else if height == 0 {
+			height = loader.placed[len(loader.placed)-1].size[2]
+		}
+	}
+	return height, left
+}
+
+// placeInLayer load a box at the first corner of the layer's floor where it
+// fits in one of the given orientations, returning false if it fits none.
+// Unless it starts the layer, the box may drop from the layer's floor onto
+// the boxes below, filling the gap above a box shorter than its own layer.
+func (loader *loader) placeInLayer(item Box, index int, orientations []Orientation, starts bool) bool {
+	for _, point := range loader.points {
+		corners := [][3]binpacking.Size{point}
+		if dropped := loader.project(point, 2); !starts && dropped != point {
+			corners = [][3]binpacking.Size{dropped, point}
+		}
+		for _, corner := range corners {
+			for _, orientation := range orientations {
+				space := cuboid{corner, item.dimensions(orientation)}
+				if loader.fits(space, item.Weight) {
+					loader.place(item, index, orientation, space, []int{0, 1})
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,344 @@
+// Package packing3d packs boxes into containers, such as cartons onto
+// pallets or into shipping containers, following the conventions of the
+// binpacking package: a PackingList names the algorithm and items, and the
+// solution lists each container with the position of every box loaded.
+package packing3d
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// Algorithm types of box packing algorithms supported by the package
+type Algorithm int
+
+const (
+	// Unknown for default value initialization
+	Unknown Algorithm = iota
+	// ExtremePoint places each box at the lowest, then rearmost, then
+	// leftmost of the corners the boxes already loaded leave, in the first
+	// container it fits, as in Crainic, Perboli and Tadei's extreme points
+	ExtremePoint
+	// LayerBuilding fills a container in horizontal layers, each as tall as
+	// the box that starts it, before starting the next layer above
+	LayerBuilding
+)
+
+var names = []string{
+	"Unknown",
+	"ExtremePoint",
+	"LayerBuilding"}
+
+// packers the packing function of each algorithm
+var packers = []func(*ContainerCollection, context.Context, []Box, []int) error{
+	nil,
+	(*ContainerCollection).packExtremePoints,
+	(*ContainerCollection).packLayers}
+
+// Algorithms get the names of every algorithm
+func Algorithms() []string {
+	return append([]string{}, names[1:]...)
+}
+
+func (algorithm Algorithm) String() string {
+	if algorithm < 0 || int(algorithm) >= len(names) {
+		return names[Unknown]
+	}
+	return names[algorithm]
+}
+
+// MarshalJSON algorithms are written by name
+func (algorithm Algorithm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algorithm.String())
+}
+
+// UnmarshalJSON read an algorithm from either its name or its number
+func (algorithm *Algorithm) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*algorithm = GetAlgorithm(name)
+		return nil
+	}
+	var number int
+	if err := json.Unmarshal(b, &number); err != nil {
+		return err
+	}
+	*algorithm = Algorithm(number)
+	return nil
+}
+
+// GetAlgorithm get an algorithm from string, Unknown if there is none
+func GetAlgorithm(s string) Algorithm {
+	for i, name := range names {
+		if name == s {
+			return Algorithm(i)
+		}
+	}
+	return Unknown
+}
+
+// Orientation a way of standing a box in a container, naming which of its
+// sides runs along the container's width, depth and height
+type Orientation int
+
+const (
+	// Upright the box as given: width along the width, depth along the depth, height up
+	Upright Orientation = iota
+	// UprightTurned the box turned about its height, so its width runs along the depth
+	UprightTurned
+	// OnSide the box laid on its side, with its depth up and its width along the width
+	OnSide
+	// OnSideTurned the box laid on its side, with its depth up and its height along the width
+	OnSideTurned
+	// OnEnd the box stood on its end, with its width up and its depth along the width
+	OnEnd
+	// OnEndTurned the box stood on its end, with its width up and its height along the width
+	OnEndTurned
+)
+
+var orientationNames = []string{
+	"Upright",
+	"UprightTurned",
+	"OnSide",
+	"OnSideTurned",
+	"OnEnd",
+	"OnEndTurned"}
+
+// allOrientations the orientations of a box which does not list any
+var allOrientations = []Orientation{Upright, UprightTurned, OnSide, OnSideTurned, OnEnd, OnEndTurned}
+
+func (orientation Orientation) String() string {
+	if orientation < 0 || int(orientation) >= len(orientationNames) {
+		return "Invalid"
+	}
+	return orientationNames[orientation]
+}
+
+// MarshalJSON orientations are written by name
+func (orientation Orientation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orientation.String())
+}
+
+// UnmarshalJSON read an orientation from either its name or its number
+func (orientation *Orientation) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*orientation = -1
+		for i, orientationName := range orientationNames {
+			if orientationName == name {
+				*orientation = Orientation(i)
+			}
+		}
+		return nil
+	}
+	var number int
+	if err := json.Unmarshal(b, &number); err != nil {
+		return err
+	}
+	*orientation = Orientation(number)
+	return nil
+}
+
+// Box an item to be packed
+type Box struct {
+	Width  binpacking.Size `json:"width"`
+	Depth  binpacking.Size `json:"depth"`
+	Height binpacking.Size `json:"height"`
+	Weight binpacking.Size `json:"weight,omitempty"`
+	// Orientations the ways the box may be stood, any way when empty.
+	// Upright and UprightTurned alone keep a box the right way up.
+	Orientations []Orientation `json:"orientations,omitempty"`
+}
+
+// Volume the volume of the box
+func (box Box) Volume() binpacking.Size {
+	return box.Width * box.Depth * box.Height
+}
+
+// allowed the orientations the box may be stood in
+func (box Box) allowed() []Orientation {
+	if len(box.Orientations) == 0 {
+		return allOrientations
+	}
+	return box.Orientations
+}
+
+// dimensions the size of the box along the container's width, depth and
+// height when stood in the given orientation
+func (box Box) dimensions(orientation Orientation) [3]binpacking.Size {
+	w, d, h := box.Width, box.Depth, box.Height
+	switch orientation {
+	case UprightTurned:
+		return [3]binpacking.Size{d, w, h}
+	case OnSide:
+		return [3]binpacking.Size{w, h, d}
+	case OnSideTurned:
+		return [3]binpacking.Size{h, w, d}
+	case OnEnd:
+		return [3]binpacking.Size{d, h, w}
+	case OnEndTurned:
+		return [3]binpacking.Size{h, d, w}
+	}
+	return [3]binpacking.Size{w, d, h}
+}
+
+// Container the size of the containers being packed, and the most weight each holds
+type Container struct {
+	Width  binpacking.Size `json:"width"`
+	Depth  binpacking.Size `json:"depth"`
+	Height binpacking.Size `json:"height"`
+	// MaxWeight the most weight of boxes a container holds, unlimited when zero
+	MaxWeight binpacking.Size `json:"maxWeight,omitempty"`
+}
+
+// dimensions the size of the container along its width, depth and height
+func (container Container) dimensions() [3]binpacking.Size {
+	return [3]binpacking.Size{container.Width, container.Depth, container.Height}
+}
+
+// Placement where a box was loaded, with its lower rear left corner at
+// X, Y, Z, where X runs along the width, Y along the depth and Z up.
+// Width, Depth and Height are as loaded, in the given orientation.
+type Placement struct {
+	// Index the position of the box in the input passed to PackAll
+	Index       int             `json:"index"`
+	X           binpacking.Size `json:"x"`
+	Y           binpacking.Size `json:"y"`
+	Z           binpacking.Size `json:"z"`
+	Width       binpacking.Size `json:"width"`
+	Depth       binpacking.Size `json:"depth"`
+	Height      binpacking.Size `json:"height"`
+	Orientation Orientation     `json:"orientation"`
+}
+
+// Load a container and the boxes loaded into it
+type Load struct {
+	Container
+	Placements []Placement `json:"placements"`
+	// Usage the total volume of the boxes in the container
+	Usage binpacking.Size `json:"usage"`
+	// Weight the total weight of the boxes in the container
+	Weight binpacking.Size `json:"weight"`
+}
+
+// PackingList input for a box packing problem
+type PackingList struct {
+	// Container the size and weight limit of the containers being packed
+	Container
+	// Algorithm the algorithm being used to solve the problem
+	Algorithm Algorithm `json:"algorithm"`
+	// Items the actual boxes being passed in
+	Items []Box `json:"items"`
+	// Support the least fraction of its base a box not on the floor must
+	// rest on the tops of other boxes, not checked when zero
+	Support float64 `json:"support,omitempty"`
+}
+
+// ContainerCollection an instance of the box packing problem
+type ContainerCollection struct {
+	Container
+	TotalContainers binpacking.Count  `json:"count"`
+	Loads           []Load            `json:"containers"`
+	Algorithm       Algorithm         `json:"algorithm"`
+	Support         float64           `json:"support,omitempty"`
+	Status          binpacking.Status `json:"status"`
+	SolutionTime    int64             `json:"solution_time"`
+}
+
+// NewContainerCollection create an instance of the box packing problem from a PackingList object
+func NewContainerCollection(list *PackingList) *ContainerCollection {
+	return &ContainerCollection{
+		Container: list.Container,
+		Loads:     make([]Load, 0),
+		Algorithm: list.Algorithm,
+		Support:   list.Support}
+}
+
+// PackAll pack all of the given boxes
+func (collection *ContainerCollection) PackAll(items []Box) error {
+	return collection.PackAllContext(context.Background(), items)
+}
+
+// PackAllContext pack all of the given boxes, largest volume first, giving
+// up when the context is done. Boxes are only stood in the orientations they
+// allow, no container takes more than its weight limit, and when Support is
+// set, each box off the floor rests on enough of the boxes below.
+func (collection *ContainerCollection) PackAllContext(ctx context.Context, items []Box) error {
+	if collection.Algorithm <= Unknown || int(collection.Algorithm) >= len(names) {
+		return &UnknownAlgorithmError{Algorithm: collection.Algorithm}
+	}
+	if collection.Width <= 0 || collection.Depth <= 0 || collection.Height <= 0 || collection.MaxWeight < 0 {
+		return &InvalidContainerError{Container: collection.Container}
+	}
+	for i, item := range items {
+		if err := collection.validate(i, item); err != nil {
+			return err
+		}
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		first, second := items[order[a]], items[order[b]]
+		if first.Volume() != second.Volume() {
+			return first.Volume() > second.Volume()
+		}
+		return first.Weight > second.Weight
+	})
+
+	collection.Loads = make([]Load, 0)
+	collection.Status = binpacking.Unsolved
+	if err := packers[collection.Algorithm](collection, ctx, items, order); err != nil {
+		return err
+	}
+	collection.TotalContainers = binpacking.Count(len(collection.Loads))
+	collection.Status = binpacking.Feasible
+	return nil
+}
+
+// validate check that a box fits an empty container in an orientation it allows
+func (collection *ContainerCollection) validate(index int, item Box) error {
+	if item.Width <= 0 || item.Depth <= 0 || item.Height <= 0 || item.Weight < 0 {
+		return &InvalidBoxError{Index: index, Box: item}
+	}
+	for _, orientation := range item.Orientations {
+		if orientation < Upright || orientation > OnEndTurned {
+			return &InvalidBoxError{Index: index, Box: item}
+		}
+	}
+	if collection.MaxWeight > 0 && item.Weight > collection.MaxWeight {
+		return &OversizeBoxError{Index: index, Box: item, Container: collection.Container}
+	}
+	for _, orientation := range item.allowed() {
+		if fitsWithin(item.dimensions(orientation), collection.dimensions()) {
+			return nil
+		}
+	}
+	return &OversizeBoxError{Index: index, Box: item, Container: collection.Container}
+}
+
+// GetTotalBins getter for the number of containers used
+func (collection *ContainerCollection) GetTotalBins() binpacking.Count {
+	return collection.TotalContainers
+}
+
+// String get the JSON representation of the solution
+func (collection *ContainerCollection) String() string {
+	jsonString, _ := json.MarshalIndent(collection, "", "  ")
+	return string(jsonString)
+}
+
+// SetTime set the execution time for a single run
+func (collection *ContainerCollection) SetTime(nanoseconds int64) {
+	collection.SolutionTime = nanoseconds
+}
+
+// fitsWithin whether a size is no larger than another along every axis
+func fitsWithin(size, room [3]binpacking.Size) bool {
+	return size[0] <= room[0] && size[1] <= room[1] && size[2] <= room[2]
+}
@@ -0,0 +1,91 @@
+package packing3d
+
+import "github.com/gnboorse/binpacking"
+
+// Verify check that a solution loads each box of the packing list once, in
+// an orientation it allows, that no box leaves its container or overlaps
+// another, that no container is over its weight limit, that every box off
+// the floor rests on enough of the boxes below when the list asks for
+// support, and that the solution's bookkeeping is consistent. Containers
+// with boxes outside them, overlapping or too heavy are reported as
+// overfull, and those with boxes resting on too little as unsupported.
+func Verify(list *PackingList, sol *ContainerCollection) *binpacking.VerificationReport {
+	report := &binpacking.VerificationReport{
+		TotalBins:  sol.TotalContainers,
+		ActualBins: binpacking.Count(len(sol.Loads))}
+	used := make([]bool, len(list.Items))
+	for i, load := range sol.Loads {
+		valid, overfull, unsupported := true, false, false
+		var volume, weight binpacking.Size
+		spaces := make([]cuboid, len(load.Placements))
+		for j, placement := range load.Placements {
+			spaces[j] = cuboid{
+				[3]binpacking.Size{placement.X, placement.Y, placement.Z},
+				[3]binpacking.Size{placement.Width, placement.Depth, placement.Height}}
+		}
+		for j, placement := range load.Placements {
+			space := spaces[j]
+			volume += space.size[0] * space.size[1] * space.size[2]
+			if space.corner[0] < 0 || space.corner[1] < 0 || space.corner[2] < 0 ||
+				!fitsWithin([3]binpacking.Size{space.end(0), space.end(1), space.end(2)}, list.dimensions()) {
+				overfull = true
+			}
+			for _, other := range spaces[:j] {
+				overfull = overfull || space.overlaps(other)
+			}
+			if !supported(space, spaces, list.Support) {
+				unsupported = true
+			}
+			index := placement.Index
+			if index < 0 || index >= len(list.Items) || used[index] || !matches(list.Items[index], placement) {
+				valid = false
+				continue
+			}
+			used[index] = true
+			weight += list.Items[index].Weight
+		}
+		if list.MaxWeight > 0 && weight > list.MaxWeight {
+			overfull = true
+		}
+		if !valid {
+			report.IndexMismatches = append(report.IndexMismatches, i)
+		}
+		if overfull {
+			report.OverfullBins = append(report.OverfullBins, i)
+		}
+		if unsupported {
+			report.UnsupportedBins = append(report.UnsupportedBins, i)
+		}
+		if load.Container != list.Container {
+			report.CapacityMismatches = append(report.CapacityMismatches, i)
+		}
+		if volume != load.Usage || weight != load.Weight {
+			report.UsageMismatches = append(report.UsageMismatches, i)
+		}
+	}
+	for index, placed := range used {
+		if !placed {
+			report.MissingIndices = append(report.MissingIndices, index)
+		}
+	}
+
+	report.Valid = len(report.MissingIndices) == 0 &&
+		len(report.OverfullBins) == 0 &&
+		len(report.UnsupportedBins) == 0 &&
+		len(report.CapacityMismatches) == 0 &&
+		len(report.UsageMismatches) == 0 &&
+		len(report.IndexMismatches) == 0 &&
+		report.TotalBins == report.ActualBins
+	return report
+}
+
+// matches whether a placement has the size of the input box it records in
+// the orientation it gives, which the box allows
+func matches(item Box, placement Placement) bool {
+	allowed := false
+	for _, orientation := range item.allowed() {
+		allowed = allowed || orientation == placement.Orientation
+	}
+	return allowed && item.dimensions(placement.Orientation) ==
+		[3]binpacking.Size{placement.Width, placement.Depth, placement.Height}
+}
@@ -0,0 +1,163 @@
+package binpacking
+
+import (
+	"context"
+	"sort"
+)
+
+// Reduce apply Martello and Toth's reduction procedure (MTRP), fixing bins
+// which some optimal packing is known to contain. Returns the fixed bins,
+// whose Indices are positions in items, and the items left to pack in their
+// original order. Packing the remaining items optimally and adding the fixed
+// bins gives an optimal packing of all items.
+// The items passed in are not modified.
+func Reduce(items Items, capacity Size) (Bins, Items) {
+	fixed, free := reduce(items, capacity)
+	bins := make(Bins, len(fixed))
+	for i, positions := range fixed {
+		bins[i] = NewBin(capacity)
+		for _, position := range positions {
+			bins[i].PackIndex(items[position], position)
+		}
+	}
+	sort.Ints(free)
+	remaining := make(Items, len(free))
+	for i, position := range free {
+		remaining[i] = items[position]
+	}
+	return bins, remaining
+}
+
+// reduce apply the dominance criteria of Martello and Toth's reduction
+// procedure (MTRP): whenever the bin for some item can be fixed without
+// losing optimality, fix it and remove its items from the problem.
+// Returns the fixed bins and the items left over, as positions in items.
+func reduce(items Items, capacity Size) ([][]int, []int) {
+	free := items.decreasingOrder()
+	fixed := make([][]int, 0)
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(free); i++ {
+			bin := dominantBin(items, free, i, capacity)
+			if bin == nil {
+				continue
+			}
+			fixed = append(fixed, bin)
+			free = withoutPositions(free, bin)
+			changed = true
+			i-- // the next item has moved into this slot
+		}
+	}
+	return fixed, free
+}
+
+// dominantBin find a bin for the item at free[index] that dominates every
+// other bin it could be packed in, or nil if there is no such bin.
+// Free holds positions of the unfixed items in decreasing order of size.
+// Only bins completed by a single item are considered, which is dominant
+// when no other completion is larger than that item.
+func dominantBin(items Items, free []int, index int, capacity Size) []int {
+	item := free[index]
+	residual := capacity - Size(items[item])
+	// others the sizes of the other free items, in decreasing order
+	othersLen := len(free) - 1
+	other := func(i int) Size {
+		if i >= index {
+			i++
+		}
+		return Size(items[free[i]])
+	}
+
+	// the other items from first onwards are those which fit alongside this one
+	first := sort.Search(othersLen, func(i int) bool { return other(i) <= residual })
+	if first == othersLen {
+		return []int{item} // nothing fits alongside this item
+	}
+	largest := first
+	if largest >= index {
+		largest++
+	}
+	largest = free[largest]
+	largestSize := other(first)
+	if largestSize == residual {
+		return []int{item, largest} // a single item fills the bin
+	}
+	fitting := othersLen - first
+	if fitting < 2 || other(othersLen-1)+other(othersLen-2) > residual {
+		return []int{item, largest} // no two items fit alongside this one
+	}
+	if fitting >= 3 && other(othersLen-1)+other(othersLen-2)+other(othersLen-3) <= residual {
+		return nil // completions of three or more items are not checked
+	}
+	// find the largest pair that fits alongside this item
+	var bestPair Size
+	for high, low := first, othersLen-1; high < low; {
+		if pair := other(high) + other(low); pair <= residual {
+			if pair > bestPair {
+				bestPair = pair
+			}
+			low--
+		} else {
+			high++
+		}
+	}
+	if largestSize >= bestPair {
+		return []int{item, largest} // every completion fits in the space of the largest item
+	}
+	return nil
+}
+
+// withoutPositions remove the given positions from a list of positions
+func withoutPositions(positions []int, remove []int) []int {
+	removed := make(map[int]bool, len(remove))
+	for _, position := range remove {
+		removed[position] = true
+	}
+	kept := make([]int, 0, len(positions))
+	for _, position := range positions {
+		if !removed[position] {
+			kept = append(kept, position)
+		}
+	}
+	return kept
+}
+
+// packReduced pack items by fixing bins with Reduce, then packing what is
+// left in a collection of its own, so packers see an ordinary problem, and
+// moving those bins and the packer's results into this collection
+func (binCollection *BinCollectionImpl) packReduced(ctx context.Context, packer Packer, items Items) error {
+	fixed, free := reduce(items, binCollection.BinCapacity)
+	for _, positions := range fixed {
+		bin := binCollection.NewBin()
+		for _, position := range positions {
+			bin.PackIndex(items[position], position)
+		}
+	}
+	sort.Ints(free) // the packer gets the remaining items in the caller's order
+	remaining := make(Items, len(free))
+	for i, position := range free {
+		remaining[i] = items[position]
+	}
+
+	residual := *binCollection
+	residual.Bins = make(Bins, 0)
+	residual.TotalBins = 0
+	if report := binCollection.OnImprovement; report != nil {
+		reduced := binCollection.TotalBins
+		residual.OnImprovement = func(bins Count) { report(reduced + bins) } // count the fixed bins too
+	}
+	if err := packer.PackAll(ctx, &residual, remaining); err != nil {
+		return err
+	}
+	for _, bin := range residual.Bins {
+		for i, position := range bin.Indices {
+			bin.Indices[i] = free[position] // positions in remaining back to positions in items
+		}
+		binCollection.Bins = append(binCollection.Bins, bin)
+		binCollection.TotalBins++
+	}
+	binCollection.Status = residual.Status
+	binCollection.Nodes = residual.Nodes
+	binCollection.MaxOpenBins = residual.MaxOpenBins
+	return nil
+}
@@ -0,0 +1,342 @@
+package binpacking
+
+import "sort"
+
+// Session a packing which items join and leave one at a time, for long-lived
+// allocators. Each item is placed by an online policy when it arrives, and
+// after every insertion or removal up to RepackBudget items may be moved
+// between bins to empty a bin. Items are identified by the number of items
+// inserted before them, and bins by the number opened before them, so a
+// session only keeps the items it holds, however many have come and gone.
+type Session struct {
+	// Metrics how many bins the session has used over time
+	Metrics    SessionMetrics
+	collection *BinCollectionImpl // bins whose Indices are the IDs of their items
+	policy     sessionPolicy
+	binIDs     []int       // ID of each bin in the collection
+	classes    []int       // harmonic class of the items each bin takes, zero when not classed
+	binOf      map[int]int // ID of the bin holding each item in the session, by item ID
+	nextItem   int
+	nextBin    int
+}
+
+// SessionMetrics how many bins a session has used over time
+type SessionMetrics struct {
+	// Operations the number of insertions and removals so far
+	Operations int `json:"operations"`
+	// PeakBins the most bins in use at once
+	PeakBins Count `json:"peakBins"`
+	// Migrations the number of items moved between bins to empty a bin
+	Migrations int `json:"migrations"`
+	// BinsOverTime the number of bins in use after every SampleInterval
+	// operations, so entry i is the count after operation (i+1)*SampleInterval.
+	// Once it has sessionSamples entries, every other one is dropped and the
+	// interval doubles, so it stays small however long the session runs.
+	BinsOverTime []Count `json:"binsOverTime"`
+	// SampleInterval the number of operations between entries of BinsOverTime
+	SampleInterval int `json:"sampleInterval"`
+}
+
+// sessionSamples the most entries a session keeps in BinsOverTime
+const sessionSamples = 1024
+
+// sessionPolicy how a session chooses the bin for an arriving item among
+// those it fits: the first opened, the fullest or the emptiest
+type sessionPolicy struct {
+	best  bool
+	worst bool
+	// classed bins only take items of one harmonic class, as in Harmonic
+	classed bool
+}
+
+// sessionPolicies the online algorithms a session can place items with
+var sessionPolicies = map[Algorithm]sessionPolicy{
+	FirstFit: {},
+	BestFit:  {best: true},
+	WorstFit: {worst: true},
+	Harmonic: {classed: true},
+}
+
+// SessionAlgorithms get the names of the algorithms which can place items in a Session
+func SessionAlgorithms() []string {
+	algorithms := make([]Algorithm, 0, len(sessionPolicies))
+	for algorithm := range sessionPolicies {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool { return algorithms[i] < algorithms[j] })
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = algorithm.String()
+	}
+	return names
+}
+
+// NewSession start an empty session packing bins of the list's size, holding
+// at most its MaxItems items each, with the list's algorithm and options.
+// Harmonic keeps the items of each of its K classes in bins of their own, and
+// packs them first fit, since bins it would close may reopen when items leave.
+func NewSession(list *PackingList) (*Session, error) {
+	policy, ok := sessionPolicies[list.Algorithm]
+	if !ok {
+		return nil, &UnknownAlgorithmError{Algorithm: list.Algorithm}
+	}
+	if list.Size <= 0 {
+		return nil, &InvalidCapacityError{Capacity: list.Size}
+	}
+	if list.MaxItems < 0 {
+		return nil, &InvalidMaxItemsError{MaxItems: list.MaxItems}
+	}
+	collection := &BinCollectionImpl{
+		BinCapacity: list.Size,
+		Bins:        make(Bins, 0),
+		Algorithm:   list.Algorithm,
+		Status:      Feasible,
+		MaxItems:    list.MaxItems,
+		Options:     list.Options}
+	if policy.classed && collection.K <= 0 {
+		collection.K = defaultHarmonicK
+	}
+	return &Session{
+		Metrics:    SessionMetrics{BinsOverTime: make([]Count, 0), SampleInterval: 1},
+		collection: collection,
+		policy:     policy,
+		binOf:      make(map[int]int)}, nil
+}
+
+// Packing a copy of the bins in use, in the order they were opened. Its input
+// is the items the session holds, in the order they were inserted, and the
+// bins' Indices are positions in it, so it can be checked with Verify
+// against a list of those items.
+func (session *Session) Packing() *BinCollectionImpl {
+	itemIDs := make([]int, 0, len(session.binOf))
+	for itemID := range session.binOf {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Ints(itemIDs)
+	positions := make(map[int]int, len(itemIDs))
+	for position, itemID := range itemIDs {
+		positions[itemID] = position
+	}
+
+	packing := *session.collection
+	packing.Bins = make(Bins, len(session.collection.Bins))
+	packing.input = make(Items, len(itemIDs))
+	for i, bin := range session.collection.Bins {
+		packing.Bins[i] = bin
+		packing.Bins[i].Items = make(Items, len(bin.Items))
+		copy(packing.Bins[i].Items, bin.Items)
+		packing.Bins[i].Indices = make([]int, len(bin.Indices))
+		for j, itemID := range bin.Indices {
+			packing.Bins[i].Indices[j] = positions[itemID]
+			packing.input[positions[itemID]] = bin.Items[j]
+		}
+	}
+	return &packing
+}
+
+// BinOf the ID of the bin holding an item, false if the session does not hold it
+func (session *Session) BinOf(itemID int) (int, bool) {
+	binID, ok := session.binOf[itemID]
+	return binID, ok
+}
+
+// Bin a copy of the bin with the given ID, whose Indices are the IDs of the
+// items it holds, false if the bin is not in use
+func (session *Session) Bin(binID int) (*Bin, bool) {
+	index := session.binIndex(binID)
+	if index < 0 {
+		return nil, false
+	}
+	bin := *session.collection.binAt(index)
+	bin.Items = make(Items, len(bin.Items))
+	copy(bin.Items, session.collection.binAt(index).Items)
+	bin.Indices = make([]int, len(bin.Indices))
+	copy(bin.Indices, session.collection.binAt(index).Indices)
+	return &bin, true
+}
+
+// Insert place an item, returning the ID of the bin holding it once
+// any repacking is done. The item's ID is the number of items inserted before it.
+func (session *Session) Insert(item Item) (int, error) {
+	if err := validateItem(-1, item, session.collection.BinCapacity); err != nil {
+		return -1, err
+	}
+	itemID := session.nextItem
+	session.nextItem++
+	index := session.choose(item, -1)
+	if index < 0 {
+		index = session.openBin(item)
+	}
+	session.collection.binAt(index).PackIndex(item, itemID)
+	session.binOf[itemID] = session.binIDs[index]
+	session.repack()
+	session.record()
+	return session.binOf[itemID], nil
+}
+
+// Remove take an item out of the session, closing its bin if it is left empty
+func (session *Session) Remove(itemID int) error {
+	binID, ok := session.binOf[itemID]
+	if !ok {
+		return &UnknownItemError{ID: itemID}
+	}
+	index := session.binIndex(binID)
+	bin := session.collection.binAt(index)
+	for j, id := range bin.Indices {
+		if id == itemID {
+			bin.unpack(j)
+			break
+		}
+	}
+	delete(session.binOf, itemID)
+	if len(bin.Items) == 0 {
+		session.closeBin(index)
+	}
+	session.repack()
+	session.record()
+	return nil
+}
+
+// choose the bin the policy places an item in, other than the excluded bin,
+// -1 if no bin it may go in can fit it
+func (session *Session) choose(item Item, exclude int) int {
+	class := session.class(item)
+	chosen := -1
+	for i := range session.collection.Bins {
+		bin := session.collection.binAt(i)
+		if i == exclude || session.classes[i] != class || !bin.CanFit(item) {
+			continue
+		}
+		if chosen < 0 {
+			chosen = i
+			continue
+		}
+		remaining := session.collection.binAt(chosen).Remaining()
+		if (session.policy.best && bin.Remaining() < remaining) || (session.policy.worst && bin.Remaining() > remaining) {
+			chosen = i
+		}
+	}
+	return chosen
+}
+
+// class the harmonic class of an item when bins are classed, zero otherwise
+func (session *Session) class(item Item) int {
+	if !session.policy.classed {
+		return 0
+	}
+	return harmonicClass(item, session.collection.BinCapacity, session.collection.K)
+}
+
+// openBin open a new bin for items of the same class as the given item,
+// returning its index
+func (session *Session) openBin(item Item) int {
+	session.collection.NewBin()
+	session.binIDs = append(session.binIDs, session.nextBin)
+	session.classes = append(session.classes, session.class(item))
+	session.nextBin++
+	if session.collection.TotalBins > session.Metrics.PeakBins {
+		session.Metrics.PeakBins = session.collection.TotalBins
+	}
+	return len(session.collection.Bins) - 1
+}
+
+// closeBin drop the bin at an index, keeping the others in the order they were opened
+func (session *Session) closeBin(index int) {
+	session.collection.Bins = append(session.collection.Bins[:index], session.collection.Bins[index+1:]...)
+	session.binIDs = append(session.binIDs[:index], session.binIDs[index+1:]...)
+	session.classes = append(session.classes[:index], session.classes[index+1:]...)
+	session.collection.TotalBins--
+}
+
+// binIndex the index of the bin with the given ID, -1 if it is not open
+func (session *Session) binIndex(binID int) int {
+	for i, id := range session.binIDs {
+		if id == binID {
+			return i
+		}
+	}
+	return -1
+}
+
+// repack empty whole bins by moving their items into the other bins as the
+// policy places them, while the budget allows. Bins with the fewest items,
+// then the least usage, are tried first, each at most once.
+func (session *Session) repack() {
+	budget := session.collection.RepackBudget
+	tried := make(map[int]bool)
+	for budget > 0 {
+		index := -1
+		for i := range session.collection.Bins {
+			bin := session.collection.binAt(i)
+			if tried[session.binIDs[i]] || len(bin.Items) > budget {
+				continue
+			}
+			if index < 0 {
+				index = i
+				continue
+			}
+			fewest := session.collection.binAt(index)
+			if len(bin.Items) < len(fewest.Items) || (len(bin.Items) == len(fewest.Items) && bin.Usage < fewest.Usage) {
+				index = i
+			}
+		}
+		if index < 0 {
+			return
+		}
+		tried[session.binIDs[index]] = true
+		budget -= session.evacuate(index)
+	}
+}
+
+// evacuate move every item out of the bin at an index, largest first, and
+// close it, returning the number of items moved. If some item does not fit
+// in another bin, the items already moved are put back and none are moved.
+func (session *Session) evacuate(index int) int {
+	source := session.collection.binAt(index)
+	items := make(Items, len(source.Items))
+	copy(items, source.Items)
+	itemIDs := make([]int, len(source.Indices))
+	copy(itemIDs, source.Indices)
+
+	order := items.decreasingOrder()
+	targets := make([]int, 0, len(items))
+	for _, j := range order {
+		target := session.choose(items[j], index)
+		if target < 0 {
+			// each target's last item is the one moved there most recently
+			for k := len(targets) - 1; k >= 0; k-- {
+				bin := session.collection.binAt(targets[k])
+				bin.unpack(len(bin.Items) - 1)
+			}
+			return 0
+		}
+		session.collection.binAt(target).PackIndex(items[j], itemIDs[j])
+		targets = append(targets, target)
+	}
+	for k, j := range order {
+		session.binOf[itemIDs[j]] = session.binIDs[targets[k]]
+	}
+	session.closeBin(index)
+	session.Metrics.Migrations += len(items)
+	return len(items)
+}
+
+// record note the number of bins in use at the end of an operation, if it
+// is one sampled, halving the samples kept once there are too many
+func (session *Session) record() {
+	metrics := &session.Metrics
+	metrics.Operations++
+	if metrics.Operations%metrics.SampleInterval != 0 {
+		return
+	}
+	metrics.BinsOverTime = append(metrics.BinsOverTime, session.collection.TotalBins)
+	if len(metrics.BinsOverTime) > sessionSamples {
+		// keep the entries after operations which are multiples of twice the interval
+		kept := metrics.BinsOverTime[:0]
+		for i := 1; i < len(metrics.BinsOverTime); i += 2 {
+			kept = append(kept, metrics.BinsOverTime[i])
+		}
+		metrics.BinsOverTime = kept
+		metrics.SampleInterval *= 2
+	}
+}
@@ -0,0 +1,204 @@
+package binpacking
+
+import "math"
+
+// simplexEpsilon tolerance below which values are treated as zero by the simplex
+const simplexEpsilon = 1e-9
+
+// simplexPivotTolerance the smallest coefficient the simplex pivots on,
+// as dividing by smaller ones magnifies rounding errors
+const simplexPivotTolerance = 1e-7
+
+// simplexDegenerateLimit the most pivots in a row which leave the objective
+// unchanged before the simplex switches to Bland's rule, which cannot cycle
+const simplexDegenerateLimit = 50
+
+// simplex the revised simplex method for linear programs of the form
+// minimize c·x subject to A·x = b, x >= 0, keeping the inverse of the
+// basis so columns can be added to a solved program cheaply, as column
+// generation does. Inequalities are written with explicit slack columns.
+type simplex struct {
+	rows    int
+	costs   []float64   // cost of each column
+	columns [][]float64 // coefficients of each column
+	bounds  []float64   // right hand side of each row
+	basis   []int       // the column basic in each row
+	inverse [][]float64 // inverse of the basis matrix
+	values  []float64   // value of the basic column in each row
+}
+
+// newSimplex set up a program starting from the given basis, one column per
+// row, which must be non-singular and give every basic variable a
+// non-negative value. Returns false if it does not.
+func newSimplex(costs []float64, columns [][]float64, bounds []float64, basis []int) (*simplex, bool) {
+	lp := &simplex{rows: len(bounds), costs: costs, columns: columns, bounds: bounds, basis: basis}
+	if !lp.invert() {
+		return nil, false
+	}
+	for _, value := range lp.values {
+		if value < -simplexEpsilon {
+			return nil, false
+		}
+	}
+	return lp, true
+}
+
+// invert compute the inverse of the basis matrix afresh by Gauss-Jordan
+// elimination, on the matrix with the identity alongside it, and the values
+// of the basic columns from it. Pivoting updates the inverse in place, which
+// gathers rounding errors, so this is repeated every so often.
+// Returns false if the basis is singular.
+func (lp *simplex) invert() bool {
+	rows := lp.rows
+	augmented := make([][]float64, rows)
+	for i := range augmented {
+		augmented[i] = make([]float64, 2*rows)
+		for k, column := range lp.basis {
+			augmented[i][k] = lp.columns[column][i]
+		}
+		augmented[i][rows+i] = 1
+	}
+	for k := 0; k < rows; k++ {
+		pivot := k
+		for i := k + 1; i < rows; i++ {
+			if math.Abs(augmented[i][k]) > math.Abs(augmented[pivot][k]) {
+				pivot = i
+			}
+		}
+		if math.Abs(augmented[pivot][k]) < simplexEpsilon {
+			return false
+		}
+		augmented[k], augmented[pivot] = augmented[pivot], augmented[k]
+		scale := augmented[k][k]
+		for j := range augmented[k] {
+			augmented[k][j] /= scale
+		}
+		for i, row := range augmented {
+			if factor := row[k]; i != k && factor != 0 {
+				for j := range row {
+					row[j] -= factor * augmented[k][j]
+				}
+			}
+		}
+	}
+	lp.inverse = make([][]float64, rows)
+	for i, row := range augmented {
+		lp.inverse[i] = row[rows:]
+	}
+	lp.values = lp.solveBasis(lp.bounds)
+	return true
+}
+
+// solveBasis multiply a vector by the inverse of the basis
+func (lp *simplex) solveBasis(vector []float64) []float64 {
+	result := make([]float64, lp.rows)
+	for i, row := range lp.inverse {
+		for k, value := range row {
+			result[i] += value * vector[k]
+		}
+	}
+	return result
+}
+
+// addColumn add a column to the program, which keeps the current basis
+func (lp *simplex) addColumn(cost float64, column []float64) {
+	lp.costs = append(lp.costs, cost)
+	lp.columns = append(lp.columns, column)
+}
+
+// solve pivot until optimal, choosing the column with the most negative
+// reduced cost to enter, or by Bland's rule after a run of degenerate
+// pivots so it cannot cycle. Returns false if the program is unbounded.
+func (lp *simplex) solve() bool {
+	degenerate, pivots := 0, 0
+	for {
+		prices := lp.dual()
+		entering := -1
+		var best float64
+		for j, column := range lp.columns {
+			reduced := lp.costs[j]
+			for i, price := range prices {
+				reduced -= price * column[i]
+			}
+			if reduced < -simplexEpsilon && (entering < 0 || reduced < best) {
+				entering, best = j, reduced
+				if degenerate >= simplexDegenerateLimit {
+					break // Bland's rule: the first improving column
+				}
+			}
+		}
+		if entering < 0 {
+			return true
+		}
+
+		direction := lp.solveBasis(lp.columns[entering])
+		leaving := -1
+		var ratio float64
+		for i, coefficient := range direction {
+			if coefficient <= simplexPivotTolerance {
+				continue
+			}
+			candidate := math.Max(lp.values[i], 0) / coefficient
+			if leaving < 0 || candidate < ratio-simplexEpsilon ||
+				(candidate < ratio+simplexEpsilon && lp.basis[i] < lp.basis[leaving]) {
+				leaving, ratio = i, candidate
+			}
+		}
+		if leaving < 0 {
+			return false
+		}
+		if ratio < simplexEpsilon {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+		lp.pivot(leaving, entering, direction)
+		if pivots++; pivots%lp.rows == 0 {
+			lp.invert() // the basis stays non-singular, by the choice of pivots
+		}
+	}
+}
+
+// pivot make a column basic in the given row, given
+// the column multiplied by the inverse of the basis
+func (lp *simplex) pivot(row, entering int, direction []float64) {
+	pivotRow := lp.inverse[row]
+	scale := direction[row]
+	for j := range pivotRow {
+		pivotRow[j] /= scale
+	}
+	lp.values[row] /= scale
+	for i, factor := range direction {
+		if i == row || factor == 0 {
+			continue
+		}
+		for j, value := range pivotRow {
+			lp.inverse[i][j] -= factor * value
+		}
+		lp.values[i] -= factor * lp.values[row]
+	}
+	lp.basis[row] = entering
+}
+
+// primal the value of each column in the current basis
+func (lp *simplex) primal() []float64 {
+	values := make([]float64, len(lp.columns))
+	for i, column := range lp.basis {
+		values[column] = lp.values[i]
+	}
+	return values
+}
+
+// dual the dual value of each row: the costs of the
+// basic columns multiplied by the inverse of the basis
+func (lp *simplex) dual() []float64 {
+	prices := make([]float64, lp.rows)
+	for i, column := range lp.basis {
+		if cost := lp.costs[column]; cost != 0 {
+			for j, value := range lp.inverse[i] {
+				prices[j] += cost * value
+			}
+		}
+	}
+	return prices
+}
@@ -0,0 +1,50 @@
+package binpacking
+
+import "encoding/json"
+
+// Status outcome of solving an instance of the bin packing problem
+type Status int
+
+const (
+	// Unsolved no packing has been produced yet
+	Unsolved Status = iota
+	// Feasible every item was packed, but the packing may not be optimal
+	Feasible
+	// Optimal the packing is proven to use the fewest bins possible
+	Optimal
+	// NotProvenOptimal an exact solver was stopped early and returned the best packing it found
+	NotProvenOptimal
+)
+
+var statusNames = []string{
+	"Unsolved",
+	"Feasible",
+	"Optimal",
+	"NotProvenOptimal"}
+
+func (status Status) String() string {
+	if status < 0 || int(status) >= len(statusNames) {
+		return statusNames[Unsolved]
+	}
+	return statusNames[status]
+}
+
+// MarshalJSON statuses are written by name
+func (status Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(status.String())
+}
+
+// UnmarshalJSON read a status from its name
+func (status *Status) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	*status = Unsolved
+	for i, statusName := range statusNames {
+		if statusName == name {
+			*status = Status(i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package binpacking
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// stockSearch state of a branch-and-bound search over the patterns
+// of a cutting stock problem
+type stockSearch struct {
+	ctx         context.Context
+	problem     *stockProblem
+	remaining   []int   // items of each size left to pack
+	chosen      [][]int // pattern of each bin packed so far
+	best        []*stockGroup
+	bestBins    Count
+	floor       Count // lower bound for the whole problem
+	nodes       int64
+	interrupted bool // the context was done before the search finished
+}
+
+// packBranchAndBound solve the problem exactly by bin completion: each bin
+// in turn is given the largest item left and then filled in every way that
+// leaves no room for another item, trying the fullest first. Each node is
+// bounded by the L2 bound of the items left, or by the items left divided
+// among bins of at most maxItems items when that is larger, and bins holding the same
+// largest item are filled in lexicographically decreasing order, so the same
+// bins are not tried in every order. The search works on the number of items
+// of each size, so it is no larger for many copies of an item than for one.
+// If the context is done before the search finishes, the best packing found
+// is used and the status is set to NotProvenOptimal.
+func (cuttingStock *CuttingStock) packBranchAndBound(ctx context.Context, problem *stockProblem) {
+	cuttingStock.search(ctx, problem, problem.heuristicGroups(), 0)
+}
+
+// packColumnGeneration solve the problem by rounding the LP relaxation
+// found by column generation, as PackAllColumnGeneration does, then
+// searching as packBranchAndBound does if that does not meet the LP bound
+func (cuttingStock *CuttingStock) packColumnGeneration(ctx context.Context, problem *stockProblem) {
+	relaxation, ok := solveRelaxation(ctx, problem)
+	if !ok {
+		cuttingStock.packBranchAndBound(ctx, problem)
+		return
+	}
+	incumbent := problem.heuristicGroups()
+	if rounded := problem.roundRelaxation(relaxation); groupCount(rounded) < groupCount(incumbent) {
+		incumbent = rounded
+	}
+	cuttingStock.search(ctx, problem, incumbent, relaxation.lowerBound())
+}
+
+// search run the branch-and-bound search from a packing, and with a lower bound, when known
+func (cuttingStock *CuttingStock) search(ctx context.Context, problem *stockProblem, incumbent []*stockGroup, floor Count) {
+	search := &stockSearch{
+		ctx:       ctx,
+		problem:   problem,
+		remaining: make([]int, len(problem.demands)),
+		best:      incumbent,
+		bestBins:  groupCount(incumbent),
+		floor:     problem.lowerBound(problem.demands)}
+	copy(search.remaining, problem.demands)
+	if floor > search.floor {
+		search.floor = floor
+	}
+
+	cuttingStock.Status = Optimal
+	if search.bestBins > search.floor {
+		search.branch()
+		if search.interrupted {
+			cuttingStock.Status = NotProvenOptimal
+		}
+	}
+	cuttingStock.Nodes = search.nodes
+	cuttingStock.setGroups(problem, search.best)
+}
+
+// branch fill the next bin in every undominated way
+func (search *stockSearch) branch() {
+	search.nodes++
+	if search.nodes%1024 == 0 && search.ctx.Err() != nil {
+		search.interrupted = true
+	}
+	if search.interrupted {
+		return
+	}
+	largest := -1
+	for i, count := range search.remaining {
+		if count > 0 {
+			largest = i
+			break
+		}
+	}
+	if largest < 0 {
+		search.record()
+		return
+	}
+	if Count(len(search.chosen))+search.problem.lowerBound(search.remaining) >= search.bestBins {
+		return
+	}
+
+	// bins with the same largest item take patterns in decreasing order
+	var limit []int
+	if previous := len(search.chosen) - 1; previous >= 0 && search.chosen[previous][largest] > 0 {
+		limit = search.chosen[previous]
+		for i := 0; i < largest; i++ {
+			if limit[i] > 0 {
+				limit = nil // the previous bin held a larger item
+				break
+			}
+		}
+	}
+	pattern := make([]int, len(search.remaining))
+	search.fill(pattern, largest, largest, search.problem.capacity, 0, limit)
+}
+
+// fill choose how many items of each size from the given one on to put in
+// the bin, which holds the given number of items so far, most first,
+// branching on each maximal pattern. The pattern is kept no greater than
+// the limit, if any, while it matches it so far.
+func (search *stockSearch) fill(pattern []int, largest, index int, room Size, items int, limit []int) {
+	if search.interrupted || search.bestBins <= search.floor {
+		return
+	}
+	sizes, capacity := search.problem.sizes, search.problem.capacity
+	if index == len(sizes) {
+		for i, count := range search.remaining {
+			if count > pattern[i] && search.problem.room(capacity-room, items, sizes[i]) > 0 {
+				return // another item fits, so a fuller pattern dominates this one
+			}
+		}
+		for i, count := range pattern {
+			search.remaining[i] -= count
+		}
+		chosen := make([]int, len(pattern))
+		copy(chosen, pattern)
+		search.chosen = append(search.chosen, chosen)
+		search.branch()
+		search.chosen = search.chosen[:len(search.chosen)-1]
+		for i, count := range pattern {
+			search.remaining[i] += count
+		}
+		return
+	}
+	most := search.remaining[index]
+	if fit := search.problem.room(capacity-room, items, sizes[index]); fit < most {
+		most = fit
+	}
+	if limit != nil && limit[index] < most {
+		most = limit[index]
+	}
+	least := 0
+	if index == largest {
+		least = 1
+	}
+	for count := most; count >= least; count-- {
+		pattern[index] = count
+		next := limit
+		if limit != nil && count < limit[index] {
+			next = nil // already smaller than the limit
+		}
+		search.fill(pattern, largest, index+1, room-Size(count)*sizes[index], items+count, next)
+	}
+	pattern[index] = 0
+}
+
+// record keep the bins packed so far as the best packing
+func (search *stockSearch) record() {
+	search.best = make([]*stockGroup, 0, len(search.chosen))
+	for _, pattern := range search.chosen {
+		group := &stockGroup{pattern: make([]int, len(pattern)), count: 1}
+		copy(group.pattern, pattern)
+		for i, count := range pattern {
+			group.load += Size(count) * search.problem.sizes[i]
+		}
+		search.best = append(search.best, group)
+	}
+	search.bestBins = Count(len(search.chosen))
+}
+
+// lowerBound Martello and Toth's L2 bound for the given number of items of
+// each size, counting the items of a size together rather than one by one.
+// When bins hold at most maxItems items, the bound is at least the number
+// of bins needed to hold that many items at a time.
+func (problem *stockProblem) lowerBound(demands []int) Count {
+	capacity, sizes := problem.capacity, problem.sizes
+	// prefix counts and sums over the sizes, largest first
+	counts := make([]int, len(sizes)+1)
+	sums := make([]Size, len(sizes)+1)
+	for i, size := range sizes {
+		counts[i+1] = counts[i] + demands[i]
+		sums[i+1] = sums[i] + Size(demands[i])*size
+	}
+	// before returns the number of sizes larger than the given size
+	before := func(size Size) int {
+		return sort.Search(len(sizes), func(i int) bool { return sizes[i] <= size })
+	}
+	best := Count(math.Ceil(float64(sums[len(sizes)]) / float64(capacity)))
+	half := before(capacity / 2) // sizes over half the capacity
+	for alpha := half; alpha <= len(sizes); alpha++ {
+		threshold := Size(0)
+		if alpha < len(sizes) {
+			threshold = sizes[alpha]
+		}
+		// items too large to share a bin with one of the threshold size
+		large := before(capacity - threshold)
+		// items sharing no bin with each other, but with room for smaller ones
+		medium := counts[half] - counts[large]
+		room := Size(medium)*capacity - (sums[half] - sums[large])
+		// items of the threshold size up to half the capacity
+		small := sums[before(threshold-1)] - sums[half]
+		bound := Count(counts[half])
+		if small > room {
+			bound += Count(ceilDivide(small-room, capacity))
+		}
+		if bound > best {
+			best = bound
+		}
+		for alpha+1 < len(sizes) && demands[alpha+1] == 0 {
+			alpha++ // sizes with no items left give the same bound
+		}
+	}
+	if problem.maxItems > 0 {
+		if bound := Count(ceilDivide(Size(counts[len(sizes)]), Size(problem.maxItems))); bound > best {
+			best = bound
+		}
+	}
+	return best
+}
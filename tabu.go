@@ -0,0 +1,97 @@
+package binpacking
+
+import (
+	"context"
+	"math/rand"
+)
+
+// defaultTabuIterations the number of moves tabu search makes by default
+const defaultTabuIterations = 10000
+
+// tabuCandidates the number of random items whose moves and swaps
+// tabu search considers at each iteration
+const tabuCandidates = 20
+
+// tabuTenure the least number of iterations an item may not return to a bin it left
+const tabuTenure = 7
+
+// tabuKey an item and a bin it may not be moved back to
+type tabuKey struct {
+	item, bin int
+}
+
+// PackAllTabu pack all items using tabu search over the bin of each item,
+// starting from first fit decreasing. Each iteration takes the best move of
+// a random item to another bin, or swap of it with an item in another bin,
+// rated by the change in the sum of the squared bin loads, even when that
+// makes things worse. An item which leaves a bin may not return to it for
+// the next few iterations, unless that would empty a bin for a new best,
+// which keeps the search from undoing its own moves. Stops after the
+// configured number of iterations, when the time limit or context is done,
+// or once the packing reaches a lower bound, in which case the status is
+// set to Optimal.
+func (binCollection *BinCollectionImpl) PackAllTabu(ctx context.Context, items Items) {
+	ctx, cancel := binCollection.withTimeLimit(ctx)
+	defer cancel()
+	iterations := binCollection.Iterations
+	if iterations <= 0 {
+		iterations = defaultTabuIterations
+	}
+	limits := binCollection.limits()
+	r := rand.New(rand.NewSource(binCollection.Seed))
+	state := newAssignment(items, limits)
+	floor := LowerBoundCardinality(items, limits.capacity, limits.maxItems)
+	best, bestBins := state.snapshot(), state.used
+	binCollection.reportBest(bestBins)
+
+	tabu := make(map[tabuKey]int) // iteration until which each move is forbidden
+	allowed := func(iteration, item, bin int, emptiesBin bool) bool {
+		return tabu[tabuKey{item, bin}] <= iteration || (emptiesBin && state.used-1 < bestBins)
+	}
+	for iteration := 0; iteration < iterations && bestBins > floor && ctx.Err() == nil; iteration++ {
+		moveItem, moveTo, swapWith := -1, -1, -1
+		var bestDelta Size
+		for candidate := 0; candidate < tabuCandidates; candidate++ {
+			item := r.Intn(len(items))
+			from := state.bin[item]
+			emptiesBin := state.loads[from] == Size(items[item])
+			for to := range state.loads {
+				if state.canMove(item, to) && allowed(iteration, item, to, emptiesBin) {
+					if delta := state.moveDelta(item, to); moveItem < 0 || delta > bestDelta {
+						moveItem, moveTo, swapWith, bestDelta = item, to, -1, delta
+					}
+				}
+			}
+			for other := range items {
+				if state.canSwap(item, other) &&
+					allowed(iteration, item, state.bin[other], false) &&
+					allowed(iteration, other, from, false) {
+					if delta := state.swapDelta(item, other); moveItem < 0 || delta > bestDelta {
+						moveItem, moveTo, swapWith, bestDelta = item, -1, other, delta
+					}
+				}
+			}
+		}
+		if moveItem < 0 {
+			continue // every candidate's moves were overfilling or tabu
+		}
+
+		tenure := iteration + tabuTenure + r.Intn(tabuTenure)
+		tabu[tabuKey{moveItem, state.bin[moveItem]}] = tenure
+		if swapWith >= 0 {
+			tabu[tabuKey{swapWith, state.bin[swapWith]}] = tenure
+			state.swap(moveItem, swapWith)
+		} else {
+			state.move(moveItem, moveTo)
+		}
+		if state.used < bestBins {
+			best, bestBins = state.snapshot(), state.used
+			binCollection.reportBest(bestBins)
+		}
+	}
+
+	binCollection.setAssignment(items, best)
+	if bestBins <= floor {
+		binCollection.Status = Optimal
+	}
+}
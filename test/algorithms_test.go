@@ -0,0 +1,149 @@
+package binpackingtests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// registeredByTests names of the algorithms registered by tests, which other
+// tests meet in Algorithms depending on the order they run in
+var registeredByTests = map[string]bool{"OneItemPerBin": true, "OneItemPerBin+LS": true}
+
+// TestRegister unit test for packing with an algorithm defined outside the package
+func TestRegister(t *testing.T) {
+	algorithm := binpacking.Register("OneItemPerBin", binpacking.PackerFunc(
+		func(ctx context.Context, binCollection *binpacking.BinCollectionImpl, items binpacking.Items) error {
+			for _, item := range items {
+				binCollection.NewBin().Pack(item)
+			}
+			return nil
+		}))
+	if binpacking.GetAlgorithm("OneItemPerBin") != algorithm {
+		t.Errorf("Registered algorithm was not found by name")
+	}
+
+	var packingList binpacking.PackingList
+	err := json.Unmarshal([]byte(`{"capacity": 10, "algorithm": "OneItemPerBin", "items": [1, 2, 3]}`), &packingList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problem := binpacking.NewBinCollection(&packingList)
+	if err := problem.PackAll(packingList.Items); err != nil {
+		t.Fatal(err)
+	}
+	if problem.GetTotalBins() != 3 {
+		t.Errorf("Registered algorithm used %v bins", problem.GetTotalBins())
+	}
+}
+
+// TestPackAllErrors unit test for the errors returned on invalid input
+func TestPackAllErrors(t *testing.T) {
+	var oversize *binpacking.OversizeItemError
+	problem := binpacking.NewBinCollection(&binpacking.PackingList{Size: 10, Algorithm: binpacking.FirstFit})
+	if err := problem.PackAll(binpacking.Items{5, 11}); !errors.As(err, &oversize) || oversize.Index != 1 {
+		t.Errorf("Expected oversize item error, got: %v", err)
+	}
+
+	var invalid *binpacking.InvalidItemError
+	problem = binpacking.NewBinCollection(&binpacking.PackingList{Size: 10, Algorithm: binpacking.FirstFit})
+	if err := problem.PackAll(binpacking.Items{0}); !errors.As(err, &invalid) {
+		t.Errorf("Expected invalid item error, got: %v", err)
+	}
+
+	var unknown *binpacking.UnknownAlgorithmError
+	problem = binpacking.NewBinCollection(&binpacking.PackingList{Size: 10})
+	if err := problem.PackAll(binpacking.Items{1}); !errors.As(err, &unknown) {
+		t.Errorf("Expected unknown algorithm error, got: %v", err)
+	}
+}
+
+// TestPackSizers unit test for mapping packed bins back to labeled items
+func TestPackSizers(t *testing.T) {
+	objects := []binpacking.Sizer{
+		binpacking.LabeledItem{ID: "a", Item: 4},
+		binpacking.LabeledItem{ID: "b", Item: 7},
+		binpacking.LabeledItem{ID: "c", Item: 4},
+		binpacking.LabeledItem{ID: "d", Item: 3}}
+	problem := &binpacking.BinCollectionImpl{BinCapacity: 10, Algorithm: binpacking.FirstFitDecreasing}
+	placed, err := problem.PackSizers(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i, bin := range placed {
+		var sum binpacking.Size
+		for j, object := range bin {
+			label := object.(binpacking.LabeledItem)
+			seen[label.ID] = true
+			sum += label.Size()
+			if objects[problem.Bins[i].Indices[j]] != object {
+				t.Errorf("Object %v does not match its recorded index", label.ID)
+			}
+		}
+		if sum != problem.Bins[i].Usage {
+			t.Errorf("Bin %v holds objects of size %v but has usage %v", i, sum, problem.Bins[i].Usage)
+		}
+	}
+	if len(placed) != 2 || len(seen) != len(objects) {
+		t.Errorf("Unexpected placement: %v", placed)
+	}
+}
+
+// TestPackAllLeavesInput unit test checking that packing does not reorder the caller's items
+func TestPackAllLeavesInput(t *testing.T) {
+	for _, name := range []string{"FirstFitDecreasing", "BinCompletion", "ModifiedFirstFitDecreasing"} {
+		items := binpacking.Items{3, 9, 1, 7}
+		problem := binpacking.NewBinCollection(&binpacking.PackingList{Size: 10, Algorithm: binpacking.GetAlgorithm(name)})
+		if err := problem.PackAll(items); err != nil {
+			t.Fatal(err)
+		}
+		binpacking.CalculateLowerBound(items, 10)
+		if !reflect.DeepEqual(items, binpacking.Items{3, 9, 1, 7}) {
+			t.Errorf("%v reordered its input: %v", name, items)
+		}
+		if !reflect.DeepEqual(problem.(*binpacking.BinCollectionImpl).GetInput(), items) {
+			t.Errorf("%v did not keep the original order", name)
+		}
+	}
+}
+
+// TestImprove unit test for following algorithms with the local search
+func TestImprove(t *testing.T) {
+	// first fit decreasing uses 4 bins for these, while 3 are enough
+	items := binpacking.Items{10, 30, 40, 25, 27, 26, 24, 27, 64, 16}
+	packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm("FirstFitDecreasing+LS"), Items: items}
+	if packingList.Algorithm == binpacking.Unknown {
+		t.Fatal("FirstFitDecreasing+LS was not registered")
+	}
+	problem := binpacking.NewBinCollection(&packingList)
+	if err := problem.PackAll(items); err != nil {
+		t.Fatal(err)
+	}
+	if report := binpacking.Verify(&packingList, problem.(*binpacking.BinCollectionImpl)); !report.Valid {
+		t.Errorf("Invalid solution: %+v", *report)
+	}
+	if problem.GetTotalBins() != 3 {
+		t.Errorf("Improved packing used %v bins", problem.GetTotalBins())
+	}
+	registered := len(binpacking.Algorithms())
+	for _, name := range []string{"NoSuchAlgorithm+LS", "FirstFitDecreasing+LS+LS"} {
+		if binpacking.GetAlgorithm(name) != binpacking.Unknown {
+			t.Errorf("%v was found", name)
+		}
+	}
+	if len(binpacking.Algorithms()) != registered {
+		t.Error("Looking up algorithms registered new ones")
+	}
+	listed := false
+	for _, name := range binpacking.Algorithms() {
+		listed = listed || name == "FirstFitDecreasing+LS"
+	}
+	if !listed {
+		t.Error("FirstFitDecreasing+LS is not listed among the algorithms")
+	}
+}
@@ -0,0 +1,64 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// optimalBinCount brute force the minimum number of bins for a small instance,
+// with no bin holding more than maxItems items when that is not zero
+func optimalBinCount(items binpacking.Items, binSize binpacking.Size, maxItems binpacking.Count) int {
+	best := len(items)
+	loads := make([]binpacking.Size, 0, len(items))
+	counts := make([]binpacking.Count, 0, len(items))
+	var assign func(i int)
+	assign = func(i int) {
+		if len(loads) >= best {
+			return
+		}
+		if i == len(items) {
+			best = len(loads)
+			return
+		}
+		for b := range loads {
+			if loads[b]+binpacking.Size(items[i]) <= binSize && (maxItems == 0 || counts[b] < maxItems) {
+				loads[b] += binpacking.Size(items[i])
+				counts[b]++
+				assign(i + 1)
+				loads[b] -= binpacking.Size(items[i])
+				counts[b]--
+			}
+		}
+		loads = append(loads, binpacking.Size(items[i]))
+		counts = append(counts, 1)
+		assign(i + 1)
+		loads = loads[:len(loads)-1]
+		counts = counts[:len(counts)-1]
+	}
+	assign(0)
+	return best
+}
+
+// TestBinCompletion unit test checking that bin completion finds the optimal solution
+func TestBinCompletion(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for instance := 0; instance < 50; instance++ {
+		items := make(binpacking.Items, 12)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(50) + 15)
+		}
+		packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.BinCompletion}
+		optimal := optimalBinCount(items, packingList.Size, 0)
+
+		problem := binpacking.NewBinCollection(&packingList)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatal(err)
+		}
+
+		if int(problem.GetTotalBins()) != optimal {
+			t.Errorf("Bin completion used %v bins for %v, optimal is %v", problem.GetTotalBins(), items, optimal)
+		}
+	}
+}
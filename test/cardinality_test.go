@@ -0,0 +1,80 @@
+package binpackingtests
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestCardinality unit test checking that every algorithm, including the
+// cutting stock solvers, keeps to the limit on items per bin, that the
+// cardinality lower bound never exceeds the optimum, and that packings
+// reported optimal are
+func TestCardinality(t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	for instance := 0; instance < 20; instance++ {
+		items := make(binpacking.Items, 10)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(30) + 1)
+		}
+		maxItems := binpacking.Count(r.Intn(3) + 2)
+		optimal := binpacking.Count(optimalBinCount(items, 100, maxItems))
+		if bound := binpacking.LowerBoundCardinality(items, 100, maxItems); bound > optimal {
+			t.Errorf("Cardinality bound %v for %v with %v items per bin exceeds the optimal %v", bound, items, maxItems, optimal)
+		}
+
+		for _, name := range binpacking.Algorithms() {
+			packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm(name),
+				Items: items, MaxItems: maxItems}
+			problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+			if err := problem.PackAll(items); err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			if report := binpacking.Verify(&packingList, problem); !report.Valid {
+				t.Errorf("Invalid %v solution for %v with %v items per bin: %+v", name, items, maxItems, *report)
+			}
+			if problem.TotalBins < optimal || (problem.Status == binpacking.Optimal && problem.TotalBins != optimal) {
+				t.Errorf("%v used %v bins for %v with %v items per bin, the optimal is %v",
+					name, problem.TotalBins, items, maxItems, optimal)
+			}
+		}
+
+		list := binpacking.CuttingStockList{Size: 100, MaxItems: maxItems}
+		for _, item := range items {
+			list.Demands = append(list.Demands, binpacking.Demand{Size: binpacking.Size(item), Quantity: 1})
+		}
+		for _, name := range binpacking.CuttingStockAlgorithms() {
+			list.Algorithm = binpacking.GetAlgorithm(name)
+			stock := binpacking.NewCuttingStock(&list)
+			if err := stock.PackAll(list.Demands); err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			if report := binpacking.VerifyCuttingStock(&list, stock); !report.Valid {
+				t.Errorf("Invalid %v cutting stock solution for %v with %v items per bin: %+v", name, items, maxItems, *report)
+			}
+			exact := list.Algorithm == binpacking.MartelloToth || list.Algorithm == binpacking.ColumnGeneration
+			if stock.TotalBins < optimal || ((stock.Status == binpacking.Optimal || exact) && stock.TotalBins != optimal) {
+				t.Errorf("%v used %v bins cutting %v with %v items per bin, the optimal is %v",
+					name, stock.TotalBins, items, maxItems, optimal)
+			}
+		}
+	}
+}
+
+// TestCardinalityErrors unit test for a negative limit on items per bin,
+// when packing bins and when cutting stock
+func TestCardinalityErrors(t *testing.T) {
+	problem := binpacking.NewBinCollection(&binpacking.PackingList{Size: 10,
+		Algorithm: binpacking.FirstFit, MaxItems: -1})
+	var invalid *binpacking.InvalidMaxItemsError
+	if err := problem.PackAll(binpacking.Items{5, 5}); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidMaxItemsError, got %v", err)
+	}
+	stock := binpacking.NewCuttingStock(&binpacking.CuttingStockList{Size: 10,
+		Algorithm: binpacking.FirstFitDecreasing, MaxItems: -1})
+	if err := stock.PackAll(binpacking.Demands{{Size: 5, Quantity: 2}}); !errors.As(err, &invalid) {
+		t.Errorf("Expected an InvalidMaxItemsError, got %v", err)
+	}
+}
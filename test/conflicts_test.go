@@ -0,0 +1,99 @@
+package binpackingtests
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestConflicts unit test checking that every algorithm supporting conflicts
+// keeps conflicting items apart, and that a set of items all in conflict
+// takes a bin each
+func TestConflicts(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for instance := 0; instance < 30; instance++ {
+		items := make(binpacking.Items, 10)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(40) + 5)
+		}
+		// the first four items are all in conflict, the rest at random
+		conflicts := make(binpacking.Conflicts, 0)
+		for first := 0; first < 4; first++ {
+			for second := first + 1; second < 4; second++ {
+				conflicts = append(conflicts, binpacking.Conflict{first, second})
+			}
+		}
+		for len(conflicts) < 12 {
+			first, second := r.Intn(len(items)), r.Intn(len(items))
+			if first != second {
+				conflicts = append(conflicts, binpacking.Conflict{first, second})
+			}
+		}
+
+		for _, name := range binpacking.ConflictAlgorithms() {
+			packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm(name),
+				Items: items, Conflicts: conflicts}
+			problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+			if err := problem.PackAll(items); err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			if report := binpacking.Verify(&packingList, problem); !report.Valid {
+				t.Errorf("Invalid %v solution for %v with %v: %+v", name, items, conflicts, *report)
+			}
+			if problem.TotalBins < 4 {
+				t.Errorf("%v packed four items in conflict into %v bins", name, problem.TotalBins)
+			}
+		}
+	}
+}
+
+// TestConflictErrors unit test for conflicts which name no item,
+// and algorithms which cannot keep conflicting items apart
+func TestConflictErrors(t *testing.T) {
+	packingList := binpacking.PackingList{Size: 10, Algorithm: binpacking.DSatur,
+		Conflicts: binpacking.Conflicts{{0, 2}}}
+	problem := binpacking.NewBinCollection(&packingList)
+	var invalid *binpacking.InvalidConflictError
+	if err := problem.PackAll(binpacking.Items{5, 5}); !errors.As(err, &invalid) || invalid.Index != 0 {
+		t.Errorf("Expected an InvalidConflictError, got %v", err)
+	}
+
+	packingList.Algorithm = binpacking.NextFit
+	problem = binpacking.NewBinCollection(&packingList)
+	var unknown *binpacking.UnknownAlgorithmError
+	if err := problem.PackAll(binpacking.Items{5, 5, 5}); !errors.As(err, &unknown) {
+		t.Errorf("Expected an UnknownAlgorithmError, got %v", err)
+	}
+
+	// no algorithm keeps conflicting items apart in bins of several types
+	packingList = binpacking.PackingList{Algorithm: binpacking.FirstFitDecreasing,
+		BinTypes: binpacking.BinTypes{{Capacity: 10, Cost: 1}}, Conflicts: binpacking.Conflicts{{0, 1}}}
+	problem = binpacking.NewBinCollection(&packingList)
+	if err := problem.PackAll(binpacking.Items{3, 3}); !errors.As(err, &unknown) {
+		t.Errorf("Expected an UnknownAlgorithmError with bin types, got %v", err)
+	}
+	packingList.Conflicts = binpacking.Conflicts{{0, 7}}
+	problem = binpacking.NewBinCollection(&packingList)
+	if err := problem.PackAll(binpacking.Items{3, 3}); !errors.As(err, &invalid) || invalid.Index != 0 {
+		t.Errorf("Expected an InvalidConflictError with bin types, got %v", err)
+	}
+}
+
+// TestVerifyConflicts unit test for detecting bins holding conflicting items
+func TestVerifyConflicts(t *testing.T) {
+	packingList := binpacking.PackingList{Size: 10, Items: binpacking.Items{3, 3, 3},
+		Conflicts: binpacking.Conflicts{{0, 2}}}
+	solution := &binpacking.BinCollectionImpl{BinCapacity: 10, TotalBins: 1}
+	bin := binpacking.NewBin(10)
+	for i, item := range packingList.Items {
+		bin.PackIndex(item, i)
+	}
+	solution.Bins = append(solution.Bins, bin)
+
+	report := binpacking.Verify(&packingList, solution)
+	if report.Valid || len(report.ConflictingBins) != 1 || report.ConflictingBins[0] != 0 {
+		t.Errorf("Unexpected report: %+v", *report)
+	}
+}
@@ -0,0 +1,54 @@
+package binpackingtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestStoppedSearch unit test checking that exact solvers stopped by their
+// context keep the packing they started from, marked not proven optimal
+func TestStoppedSearch(t *testing.T) {
+	// first fit decreasing uses three bins, where {3, 2, 2} twice uses two
+	items := binpacking.Items{3, 3, 2, 2, 2, 2}
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	for _, algorithm := range []binpacking.Algorithm{binpacking.PackingConstraint,
+		binpacking.BinCompletion, binpacking.MartelloToth} {
+		packingList := binpacking.PackingList{Size: 7, Algorithm: algorithm, Items: items}
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAllContext(ctx, items); err != nil {
+			t.Fatalf("%v: %v", algorithm, err)
+		}
+		if problem.Status != binpacking.NotProvenOptimal || problem.GetTotalBins() != 3 {
+			t.Errorf("%v stopped with %v bins and status %v", algorithm, problem.GetTotalBins(), problem.Status)
+		}
+		if report := binpacking.Verify(&packingList, problem); !report.Valid {
+			t.Errorf("Invalid %v solution: %+v", algorithm, *report)
+		}
+	}
+}
+
+// TestStoppedHeuristic unit test checking that a heuristic stopped by its
+// context returns the context's error, while the metaheuristics keep the
+// best packing found
+func TestStoppedHeuristic(t *testing.T) {
+	items := binpacking.Items{3, 3, 2, 2, 2, 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	problem := binpacking.NewBinCollection(&binpacking.PackingList{Size: 7, Algorithm: binpacking.FirstFitDecreasing})
+	if err := problem.PackAllContext(ctx, items); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the context's error, got %v", err)
+	}
+
+	packingList := binpacking.PackingList{Size: 7, Algorithm: binpacking.SimulatedAnnealing, Items: items}
+	annealing := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+	if err := annealing.PackAllContext(ctx, items); err != nil {
+		t.Fatal(err)
+	}
+	if report := binpacking.Verify(&packingList, annealing); !report.Valid || annealing.Status == binpacking.Optimal {
+		t.Errorf("Unexpected %v solution with status %v: %+v", packingList.Algorithm, annealing.Status, *report)
+	}
+}
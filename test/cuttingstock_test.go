@@ -0,0 +1,68 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestCuttingStock unit test checking that the compressed heuristics pack as
+// they do item by item, and that the exact solvers find the optimal solution
+func TestCuttingStock(t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	for instance := 0; instance < 50; instance++ {
+		list := binpacking.CuttingStockList{Size: 100}
+		items := make(binpacking.Items, 0)
+		for len(items) < 10 {
+			demand := binpacking.Demand{Size: binpacking.Size(r.Intn(60) + 10), Quantity: binpacking.Count(r.Intn(4))}
+			list.Demands = append(list.Demands, demand)
+			for i := 0; i < int(demand.Quantity); i++ {
+				items = append(items, binpacking.Item(demand.Size))
+			}
+		}
+		optimal := optimalBinCount(items, list.Size, 0)
+
+		for _, algorithm := range []binpacking.Algorithm{binpacking.FirstFitDecreasing, binpacking.BestFitDecreasing,
+			binpacking.MartelloToth, binpacking.ColumnGeneration} {
+			list.Algorithm = algorithm
+			stock := binpacking.NewCuttingStock(&list)
+			if err := stock.PackAll(list.Demands); err != nil {
+				t.Fatal(err)
+			}
+			if report := binpacking.VerifyCuttingStock(&list, stock); !report.Valid {
+				t.Errorf("Invalid %v solution: %+v", algorithm, *report)
+			}
+
+			expected := binpacking.Count(optimal)
+			if stock.Status != binpacking.Optimal {
+				packingList := binpacking.PackingList{Size: list.Size, Algorithm: algorithm}
+				problem := binpacking.NewBinCollection(&packingList)
+				if err := problem.PackAll(items); err != nil {
+					t.Fatal(err)
+				}
+				expected = problem.GetTotalBins()
+			}
+			if stock.TotalBins != expected {
+				t.Errorf("%v used %v bins for %v, expected %v", algorithm, stock.TotalBins, list.Demands, expected)
+			}
+		}
+	}
+}
+
+// TestCuttingStockMultiplicity unit test checking that large quantities
+// give a few patterns used many times
+func TestCuttingStockMultiplicity(t *testing.T) {
+	list := binpacking.CuttingStockList{Size: 100, Algorithm: binpacking.ColumnGeneration, Demands: binpacking.Demands{
+		{Size: 37, Quantity: 1200}, {Size: 45, Quantity: 800}, {Size: 18, Quantity: 1500}}}
+	stock := binpacking.NewCuttingStock(&list)
+	if err := stock.PackAll(list.Demands); err != nil {
+		t.Fatal(err)
+	}
+	if report := binpacking.VerifyCuttingStock(&list, stock); !report.Valid {
+		t.Errorf("Invalid solution: %+v", *report)
+	}
+	if stock.Status != binpacking.Optimal || len(stock.Patterns) > 10 {
+		t.Errorf("Packed with %v patterns and status %v", len(stock.Patterns), stock.Status)
+	}
+}
@@ -0,0 +1,181 @@
+package binpackingtests
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// referenceFit pack items by scanning every bin, choosing among the bins
+// that fit using the given preference, as a check on the indexed algorithms
+func referenceFit(items binpacking.Items, binSize binpacking.Size, prefer func(candidate, current binpacking.Size) bool) []binpacking.Items {
+	bins := make([]binpacking.Items, 0)
+	loads := make([]binpacking.Size, 0)
+	for _, item := range items {
+		chosen := -1
+		for i, load := range loads {
+			if load+binpacking.Size(item) <= binSize && (chosen < 0 || prefer(binSize-load, binSize-loads[chosen])) {
+				chosen = i
+			}
+		}
+		if chosen < 0 {
+			bins = append(bins, binpacking.Items{})
+			loads = append(loads, 0)
+			chosen = len(bins) - 1
+		}
+		bins[chosen] = append(bins[chosen], item)
+		loads[chosen] += binpacking.Size(item)
+	}
+	return bins
+}
+
+// packedBins get the items of every bin of a solved problem
+func packedBins(t testing.TB, algorithm binpacking.Algorithm, items binpacking.Items, binSize binpacking.Size) []binpacking.Items {
+	problem := &binpacking.BinCollectionImpl{BinCapacity: binSize, Algorithm: algorithm}
+	if err := problem.PackAll(items); err != nil {
+		t.Fatal(err)
+	}
+	bins := make([]binpacking.Items, len(problem.Bins))
+	for i, bin := range problem.Bins {
+		bins[i] = bin.Items
+	}
+	return bins
+}
+
+func randomItems(r *rand.Rand, count int, binSize binpacking.Size) binpacking.Items {
+	items := make(binpacking.Items, count)
+	for i := range items {
+		items[i] = binpacking.Item(r.Intn(int(binSize)) + 1)
+	}
+	return items
+}
+
+// TestFitSemantics unit test checking the indexed first fit and best fit against a linear scan
+func TestFitSemantics(t *testing.T) {
+	firstFit := func(candidate, current binpacking.Size) bool { return false }
+	bestFit := func(candidate, current binpacking.Size) bool { return candidate < current }
+	r := rand.New(rand.NewSource(1))
+	for instance := 0; instance < 20; instance++ {
+		items := randomItems(r, 2000, 100)
+		cases := []struct {
+			algorithm binpacking.Algorithm
+			expected  []binpacking.Items
+		}{
+			{binpacking.FirstFit, referenceFit(items, 100, firstFit)},
+			{binpacking.FirstFitDecreasing, referenceFit(items.SortedDecreasing(), 100, firstFit)},
+			{binpacking.BestFit, referenceFit(items, 100, bestFit)},
+			{binpacking.BestFitDecreasing, referenceFit(items.SortedDecreasing(), 100, bestFit)},
+		}
+		for _, c := range cases {
+			if actual := packedBins(t, c.algorithm, items, 100); !reflect.DeepEqual(actual, c.expected) {
+				t.Fatalf("%v does not match the reference packing", c.algorithm)
+			}
+		}
+	}
+}
+
+// referenceAlmostWorstFit pack items into the bin with the second most room
+// among those they fit, the earlier of two with equal room coming first, or
+// the only such bin if there is just one
+func referenceAlmostWorstFit(items binpacking.Items, binSize binpacking.Size) []binpacking.Items {
+	bins := make([]binpacking.Items, 0)
+	loads := make([]binpacking.Size, 0)
+	for _, item := range items {
+		fitting := make([]int, 0)
+		for i, load := range loads {
+			if load+binpacking.Size(item) <= binSize {
+				fitting = append(fitting, i)
+			}
+		}
+		sort.SliceStable(fitting, func(a, b int) bool { return loads[fitting[a]] < loads[fitting[b]] })
+		var chosen int
+		switch len(fitting) {
+		case 0:
+			bins = append(bins, binpacking.Items{})
+			loads = append(loads, 0)
+			chosen = len(bins) - 1
+		case 1:
+			chosen = fitting[0]
+		default:
+			chosen = fitting[1]
+		}
+		bins[chosen] = append(bins[chosen], item)
+		loads[chosen] += binpacking.Size(item)
+	}
+	return bins
+}
+
+// referenceNextFit pack items into the last bin opened, opening another when it does not fit
+func referenceNextFit(items binpacking.Items, binSize binpacking.Size) []binpacking.Items {
+	bins := []binpacking.Items{{}}
+	var load binpacking.Size
+	for _, item := range items {
+		if load+binpacking.Size(item) > binSize {
+			bins = append(bins, binpacking.Items{})
+			load = 0
+		}
+		bins[len(bins)-1] = append(bins[len(bins)-1], item)
+		load += binpacking.Size(item)
+	}
+	return bins
+}
+
+// TestWorstFitSemantics unit test checking worst fit, almost worst fit and
+// next fit decreasing against a linear scan: worst fit picks the emptiest bin,
+// almost worst fit the second emptiest, and the decreasing variants sort first
+func TestWorstFitSemantics(t *testing.T) {
+	worstFit := func(candidate, current binpacking.Size) bool { return candidate > current }
+	r := rand.New(rand.NewSource(9))
+	for instance := 0; instance < 20; instance++ {
+		items := randomItems(r, 500, 100)
+		cases := []struct {
+			algorithm binpacking.Algorithm
+			expected  []binpacking.Items
+		}{
+			{binpacking.WorstFit, referenceFit(items, 100, worstFit)},
+			{binpacking.WorstFitDecreasing, referenceFit(items.SortedDecreasing(), 100, worstFit)},
+			{binpacking.AlmostWorstFit, referenceAlmostWorstFit(items, 100)},
+			{binpacking.NextFitDecreasing, referenceNextFit(items.SortedDecreasing(), 100)},
+		}
+		for _, c := range cases {
+			if actual := packedBins(t, c.algorithm, items, 100); !reflect.DeepEqual(actual, c.expected) {
+				t.Fatalf("%v does not match the reference packing", c.algorithm)
+			}
+		}
+	}
+
+	// bins with 4, 2 and 3 left: worst fit takes the first, almost worst fit the last
+	items := binpacking.Items{6, 8, 7, 2}
+	if bins := packedBins(t, binpacking.WorstFit, items, 10); !reflect.DeepEqual(bins[0], binpacking.Items{6, 2}) {
+		t.Errorf("Worst fit did not use the emptiest bin: %v", bins)
+	}
+	if bins := packedBins(t, binpacking.AlmostWorstFit, items, 10); !reflect.DeepEqual(bins[2], binpacking.Items{7, 2}) {
+		t.Errorf("Almost worst fit did not use the second emptiest bin: %v", bins)
+	}
+}
+
+// benchmarkFit pack growing instances, so that ns/op can be compared across sizes
+func benchmarkFit(b *testing.B, algorithm binpacking.Algorithm) {
+	for _, count := range []int{1000, 10000, 100000, 1000000} {
+		items := randomItems(rand.New(rand.NewSource(1)), count, 1000000)
+		b.Run(fmt.Sprintf("%vitems", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				packedBins(b, algorithm, items, 1000000)
+			}
+		})
+	}
+}
+
+// BenchmarkFirstFit benchmark for the segment tree first fit
+func BenchmarkFirstFit(b *testing.B) {
+	benchmarkFit(b, binpacking.FirstFit)
+}
+
+// BenchmarkBestFit benchmark for the ordered multiset best fit
+func BenchmarkBestFit(b *testing.B) {
+	benchmarkFit(b, binpacking.BestFit)
+}
@@ -0,0 +1,81 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// harmonicClass the class j of an item in (binSize/(j+1), binSize/j], or k
+// when it is no larger than binSize/k
+func harmonicClass(item binpacking.Item, binSize binpacking.Size, k int) int {
+	for j := 1; j < k; j++ {
+		if binpacking.Size(item)*binpacking.Size(j+1) > binSize {
+			return j
+		}
+	}
+	return k
+}
+
+// TestHarmonic unit test checking that Harmonic keeps each class in bins of
+// its own, with at most one bin open per class, and that every variant
+// gives a valid packing for several numbers of classes
+func TestHarmonic(t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	for _, k := range []int{3, 5, 20} {
+		items := randomItems(r, 500, 100)
+		for _, algorithm := range []binpacking.Algorithm{binpacking.Harmonic,
+			binpacking.RefinedHarmonic, binpacking.ModifiedHarmonic} {
+			packingList := binpacking.PackingList{Size: 100, Algorithm: algorithm, Items: items,
+				Options: binpacking.Options{K: k}}
+			problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+			if err := problem.PackAll(items); err != nil {
+				t.Fatal(err)
+			}
+			if report := binpacking.Verify(&packingList, problem); !report.Valid {
+				t.Errorf("Invalid %v solution with K %v: %+v", algorithm, k, *report)
+			}
+			if algorithm != binpacking.Harmonic {
+				continue
+			}
+			if problem.MaxOpenBins > binpacking.Count(k) {
+				t.Errorf("Harmonic with K %v kept %v bins open", k, problem.MaxOpenBins)
+			}
+			for i, bin := range problem.Bins {
+				for _, item := range bin.Items {
+					if harmonicClass(item, 100, k) != harmonicClass(bin.Items[0], 100, k) {
+						t.Errorf("Harmonic with K %v mixed classes in bin %v: %v", k, i, bin.Items)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestHarmonicPackItem unit test checking that packing items one at a time
+// with a Harmonic variant gives the same bins as packing them all at once
+func TestHarmonicPackItem(t *testing.T) {
+	items := randomItems(rand.New(rand.NewSource(11)), 300, 100)
+	for _, algorithm := range []binpacking.Algorithm{binpacking.Harmonic,
+		binpacking.RefinedHarmonic, binpacking.ModifiedHarmonic} {
+		online := &binpacking.BinCollectionImpl{BinCapacity: 100, Algorithm: algorithm}
+		for _, item := range items {
+			if err := online.PackItem(item); err != nil {
+				t.Fatalf("%v: %v", algorithm, err)
+			}
+		}
+		actual := make([]binpacking.Items, len(online.Bins))
+		for i, bin := range online.Bins {
+			actual[i] = bin.Items
+		}
+		if expected := packedBins(t, algorithm, items, 100); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%v packed items one at a time differently", algorithm)
+		}
+		if online.MaxOpenBins == 0 {
+			t.Errorf("%v did not track its open bins when packing items one at a time", algorithm)
+		}
+	}
+}
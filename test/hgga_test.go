@@ -0,0 +1,35 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestGeneticSeed unit test checking that the genetic algorithm is valid and reproducible
+func TestGeneticSeed(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	items := make(binpacking.Items, 60)
+	for i := range items {
+		items[i] = binpacking.Item(r.Intn(40) + 10)
+	}
+	var previous binpacking.Bins
+	for run := 0; run < 2; run++ {
+		packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.HybridGroupingGenetic, Items: items}
+		packingList.Seed = 4
+		packingList.Generations = 20
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatal(err)
+		}
+		if report := binpacking.Verify(&packingList, problem); !report.Valid {
+			t.Errorf("Invalid solution: %+v", *report)
+		}
+		if previous != nil && !reflect.DeepEqual(previous, problem.Bins) {
+			t.Error("The same seed gave different packings")
+		}
+		previous = problem.Bins
+	}
+}
@@ -3,6 +3,7 @@ package binpackingtests
 import (
 	"encoding/json"
 	"io/ioutil"
+	"math/rand"
 	"testing"
 
 	"github.com/gnboorse/binpacking"
@@ -26,3 +27,31 @@ func TestCalculateLowerBound(t *testing.T) {
 	}
 
 }
+
+// TestLowerBoundsValid unit test checking that no lower bound exceeds the optimal solution
+func TestLowerBoundsValid(t *testing.T) {
+	bounds := map[string]binpacking.LowerBoundFunc{
+		"L1":                  binpacking.LowerBoundL1,
+		"L2":                  binpacking.LowerBoundL2,
+		"L3":                  binpacking.LowerBoundL3,
+		"DFF":                 binpacking.LowerBoundDFF,
+		"LP":                  binpacking.LowerBoundLP,
+		"CalculateLowerBound": binpacking.CalculateLowerBound}
+	r := rand.New(rand.NewSource(12))
+	for instance := 0; instance < 300; instance++ {
+		items := make(binpacking.Items, 10)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(70) + 1)
+		}
+		optimal := binpacking.Count(optimalBinCount(items, 100, 0))
+		best := binpacking.BestLowerBound(items, 100, binpacking.LowerBoundFunc(binpacking.LowerBoundLP))
+		if best > optimal {
+			t.Errorf("Best lower bound %v for %v exceeds the optimal %v", best, items, optimal)
+		}
+		for name, bound := range bounds {
+			if lowerBound := bound(items, 100); lowerBound > best {
+				t.Errorf("%v bound %v for %v exceeds the best lower bound %v", name, lowerBound, items, best)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package binpackingtests
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestMetaheuristicImprovements unit test checking the improvement callback of each metaheuristic
+func TestMetaheuristicImprovements(t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	items := make(binpacking.Items, 80)
+	for i := range items {
+		items[i] = binpacking.Item(r.Intn(30) + 5)
+	}
+	for _, algorithm := range []binpacking.Algorithm{binpacking.HybridGroupingGenetic, binpacking.SimulatedAnnealing, binpacking.TabuSearch} {
+		var reported []binpacking.Count
+		packingList := binpacking.PackingList{Size: 100, Algorithm: algorithm, Items: items}
+		packingList.Seed = 2
+		packingList.Iterations = 2000
+		packingList.Generations = 20
+		packingList.OnImprovement = func(bins binpacking.Count) { reported = append(reported, bins) }
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatal(err)
+		}
+		if report := binpacking.Verify(&packingList, problem); !report.Valid {
+			t.Errorf("%v produced an invalid solution: %+v", algorithm, *report)
+		}
+		if len(reported) == 0 || reported[len(reported)-1] != problem.GetTotalBins() {
+			t.Errorf("%v reported %v for a packing of %v bins", algorithm, reported, problem.GetTotalBins())
+		}
+		for i := 1; i < len(reported); i++ {
+			if reported[i] >= reported[i-1] {
+				t.Errorf("%v reported %v, which is not decreasing", algorithm, reported)
+			}
+		}
+	}
+}
+
+// TestMetaheuristicSeed unit test checking that simulated annealing and tabu
+// search give the same packing every time for the same seed
+func TestMetaheuristicSeed(t *testing.T) {
+	r := rand.New(rand.NewSource(16))
+	items := make(binpacking.Items, 60)
+	for i := range items {
+		items[i] = binpacking.Item(r.Intn(40) + 10)
+	}
+	for _, algorithm := range []binpacking.Algorithm{binpacking.SimulatedAnnealing, binpacking.TabuSearch} {
+		var previous binpacking.Bins
+		for run := 0; run < 2; run++ {
+			packingList := binpacking.PackingList{Size: 100, Algorithm: algorithm, Items: items}
+			packingList.Seed = 5
+			packingList.Iterations = 3000
+			problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+			if err := problem.PackAll(items); err != nil {
+				t.Fatal(err)
+			}
+			if report := binpacking.Verify(&packingList, problem); !report.Valid {
+				t.Errorf("Invalid %v solution: %+v", algorithm, *report)
+			}
+			if previous != nil && !reflect.DeepEqual(previous, problem.Bins) {
+				t.Errorf("The same seed gave different %v packings", algorithm)
+			}
+			previous = problem.Bins
+		}
+	}
+}
+
+// TestMetaheuristicBudgets unit test checking that the iteration budget and
+// the time limit each stop simulated annealing and tabu search
+func TestMetaheuristicBudgets(t *testing.T) {
+	// first fit decreasing uses 4 bins for these, while 3 are enough
+	improvable := binpacking.Items{10, 30, 40, 25, 27, 26, 24, 27, 64, 16}
+	// pairs of these fill two bins, but the lower bounds only count two
+	unreachable := binpacking.Items{40, 40, 40, 40, 40}
+	for _, algorithm := range []binpacking.Algorithm{binpacking.SimulatedAnnealing, binpacking.TabuSearch} {
+		reported := 0
+		packingList := binpacking.PackingList{Size: 100, Algorithm: algorithm, Items: improvable}
+		packingList.Iterations = 1
+		packingList.OnImprovement = func(bins binpacking.Count) { reported++ }
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(improvable); err != nil {
+			t.Fatal(err)
+		}
+		if reported != 1 || problem.GetTotalBins() != 4 {
+			t.Errorf("%v made %v improvements to %v bins in one iteration", algorithm, reported-1, problem.GetTotalBins())
+		}
+
+		packingList = binpacking.PackingList{Size: 100, Algorithm: algorithm, Items: unreachable}
+		packingList.Iterations = math.MaxInt32
+		packingList.TimeLimit = 20 * time.Millisecond
+		problem = binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		start := time.Now()
+		if err := problem.PackAll(unreachable); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second || problem.Status == binpacking.Optimal {
+			t.Errorf("%v ran for %v with status %v", algorithm, elapsed, problem.Status)
+		}
+	}
+}
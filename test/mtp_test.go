@@ -0,0 +1,134 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestMartelloToth unit test checking that MTP finds and proves the optimal solution
+func TestMartelloToth(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for instance := 0; instance < 50; instance++ {
+		items := make(binpacking.Items, 12)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(50) + 15)
+		}
+		packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.MartelloToth}
+		optimal := optimalBinCount(items, packingList.Size, 0)
+
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatal(err)
+		}
+
+		if int(problem.GetTotalBins()) != optimal {
+			t.Errorf("MTP used %v bins for %v, optimal is %v", problem.GetTotalBins(), items, optimal)
+		}
+		if problem.Status != binpacking.Optimal {
+			t.Errorf("MTP finished with status %v for %v", problem.Status, items)
+		}
+	}
+}
+
+// TestMartelloTothDuplicates unit test checking MTP against brute force on
+// small bins and items drawn from a few sizes, where items exactly filling a
+// bin and items of equal size often meet
+func TestMartelloTothDuplicates(t *testing.T) {
+	instances := []struct {
+		items    binpacking.Items
+		capacity binpacking.Size
+	}{
+		{binpacking.Items{7, 6, 5, 5, 9, 4}, 18},
+		{binpacking.Items{6, 8, 4, 4, 8, 9}, 20},
+		{binpacking.Items{4, 3, 5, 5, 4, 5, 3, 7, 6}, 14},
+		{binpacking.Items{8, 3, 6, 5, 2, 3, 4, 8}, 13}}
+	r := rand.New(rand.NewSource(11))
+	for len(instances) < 3000 {
+		capacity := binpacking.Size(r.Intn(15) + 8)
+		sizes := make([]binpacking.Item, r.Intn(3)+2)
+		for i := range sizes {
+			sizes[i] = binpacking.Item(r.Intn(int(capacity)*2/3) + 1)
+		}
+		items := make(binpacking.Items, r.Intn(6)+5)
+		for i := range items {
+			items[i] = sizes[r.Intn(len(sizes))]
+		}
+		instances = append(instances, struct {
+			items    binpacking.Items
+			capacity binpacking.Size
+		}{items, capacity})
+	}
+
+	for _, instance := range instances {
+		packingList := binpacking.PackingList{Size: instance.capacity, Algorithm: binpacking.MartelloToth, Items: instance.items}
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(instance.items); err != nil {
+			t.Fatal(err)
+		}
+		optimal := optimalBinCount(instance.items, instance.capacity, 0)
+		if int(problem.GetTotalBins()) != optimal || problem.Status != binpacking.Optimal {
+			t.Errorf("MTP used %v bins with status %v for %v in bins of %v, optimal is %v",
+				problem.GetTotalBins(), problem.Status, instance.items, instance.capacity, optimal)
+		}
+		if report := binpacking.Verify(&packingList, problem); !report.Valid {
+			t.Errorf("Invalid solution: %+v", *report)
+		}
+	}
+}
+
+// TestColumnGeneration unit test checking that column generation finds and
+// proves the optimal solution, including on instances where rounding the LP
+// misses its bound, so the MTP search is run from the rounded packing
+func TestColumnGeneration(t *testing.T) {
+	instances := []binpacking.PackingList{
+		{Size: 26, Items: binpacking.Items{6, 10, 7, 8, 9, 12, 9, 17, 16, 6}},
+		{Size: 45, Items: binpacking.Items{22, 20, 13, 10, 24, 14, 10, 18, 18, 18}},
+		{Size: 14, Items: binpacking.Items{6, 8, 6, 4, 3, 5, 7, 8, 5, 3}},
+		{Size: 18, Items: binpacking.Items{5, 6, 4, 9, 10, 3, 4, 6, 6}},
+		{Size: 42, Items: binpacking.Items{28, 13, 11, 13, 16, 10, 12, 17}},
+		{Size: 19, Items: binpacking.Items{3, 5, 6, 5, 5, 4, 9, 3, 9, 6}},
+		{Size: 18, Items: binpacking.Items{5, 8, 3, 3, 6, 4, 8, 3, 5, 3, 4}},
+		// the search once stopped early on these, with more bins than needed
+		{Size: 33, Items: binpacking.Items{14, 9, 8, 17, 8, 11, 14, 7, 10}},
+		{Size: 23, Items: binpacking.Items{13, 6, 6, 10, 14, 10, 11, 4, 7, 5, 5}},
+		{Size: 26, Items: binpacking.Items{5, 10, 7, 13, 5, 8, 15, 14, 6, 11, 9}},
+		{Size: 18, Items: binpacking.Items{5, 8, 4, 11, 5, 4, 5, 9, 3}},
+		{Size: 26, Items: binpacking.Items{13, 14, 8, 7, 11, 12, 6, 6, 6, 11, 9}},
+		{Size: 22, Items: binpacking.Items{4, 9, 6, 9, 9, 6, 7, 10, 4}},
+		{Size: 30, Items: binpacking.Items{20, 10, 9, 13, 11, 10, 14, 8, 6, 9, 10}}}
+	r := rand.New(rand.NewSource(17))
+	for len(instances) < 3000 {
+		capacity := binpacking.Size(r.Intn(40) + 10)
+		items := make(binpacking.Items, r.Intn(6)+6)
+		for i := range items {
+			items[i] = binpacking.Item(int(capacity)/5 + r.Intn(int(capacity)/2))
+		}
+		instances = append(instances, binpacking.PackingList{Size: capacity, Items: items})
+	}
+
+	searched := 0
+	for _, packingList := range instances {
+		packingList.Algorithm = binpacking.ColumnGeneration
+		optimal := optimalBinCount(packingList.Items, packingList.Size, 0)
+		problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+		if err := problem.PackAll(packingList.Items); err != nil {
+			t.Fatal(err)
+		}
+
+		if report := binpacking.Verify(&packingList, problem); !report.Valid {
+			t.Errorf("Invalid solution: %+v", *report)
+		}
+		if int(problem.GetTotalBins()) != optimal || problem.Status != binpacking.Optimal {
+			t.Errorf("Column generation used %v bins with status %v for %v in bins of %v, optimal is %v",
+				problem.GetTotalBins(), problem.Status, packingList.Items, packingList.Size, optimal)
+		}
+		if problem.Nodes > 0 {
+			searched++ // only the MTP search explores nodes
+		}
+	}
+	if searched < len(instances)/1000 {
+		t.Errorf("Only %v instances reached the MTP search", searched)
+	}
+}
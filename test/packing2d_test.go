@@ -0,0 +1,76 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+	"github.com/gnboorse/binpacking/packing2d"
+)
+
+// TestRectanglePacking unit test checking that every algorithm gives a valid
+// packing, with and without rotation, using no fewer sheets than the area needs
+func TestRectanglePacking(t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	for instance := 0; instance < 30; instance++ {
+		list := packing2d.PackingList{Width: 100, Height: 60}
+		var area binpacking.Size
+		for i := 0; i < 40; i++ {
+			item := packing2d.Rectangle{Width: binpacking.Size(r.Intn(50) + 1), Height: binpacking.Size(r.Intn(50) + 1)}
+			list.Items = append(list.Items, item)
+			area += item.Area()
+		}
+		bound := binpacking.Count((area + list.Width*list.Height - 1) / (list.Width * list.Height))
+
+		for _, name := range packing2d.Algorithms() {
+			for _, rotation := range []bool{false, true} {
+				list.Algorithm, list.AllowRotation = packing2d.GetAlgorithm(name), rotation
+				sheets := packing2d.NewSheetCollection(&list)
+				if err := sheets.PackAll(list.Items); err != nil {
+					t.Fatal(err)
+				}
+				if report := packing2d.Verify(&list, sheets); !report.Valid {
+					t.Errorf("Invalid %v solution with rotation %v: %+v", name, rotation, *report)
+				}
+				if sheets.TotalSheets < bound {
+					t.Errorf("%v used %v sheets, below the area bound %v", name, sheets.TotalSheets, bound)
+				}
+			}
+		}
+	}
+}
+
+// TestRectanglePackingExact unit test checking that rectangles tiling a
+// sheet exactly are packed onto one, turning them when allowed
+func TestRectanglePackingExact(t *testing.T) {
+	list := packing2d.PackingList{Width: 10, Height: 10, Items: []packing2d.Rectangle{
+		{Width: 10, Height: 4}, {Width: 5, Height: 6}, {Width: 5, Height: 6}}}
+	for _, name := range packing2d.Algorithms() {
+		list.Algorithm = packing2d.GetAlgorithm(name)
+		sheets := packing2d.NewSheetCollection(&list)
+		if err := sheets.PackAll(list.Items); err != nil {
+			t.Fatal(err)
+		}
+		if report := packing2d.Verify(&list, sheets); !report.Valid || sheets.TotalSheets != 1 {
+			t.Errorf("%v used %v sheets, expected 1: %+v", name, sheets.TotalSheets, *report)
+		}
+	}
+
+	// the same rectangles, which only tile the sheet when turned
+	list = packing2d.PackingList{Width: 10, Height: 10, Algorithm: packing2d.MaxRects, AllowRotation: true,
+		Items: []packing2d.Rectangle{{Width: 10, Height: 4}, {Width: 6, Height: 5}, {Width: 6, Height: 5}}}
+	sheets := packing2d.NewSheetCollection(&list)
+	if err := sheets.PackAll(list.Items); err != nil {
+		t.Fatal(err)
+	}
+	if report := packing2d.Verify(&list, sheets); !report.Valid || sheets.TotalSheets != 1 {
+		t.Errorf("Used %v sheets with rotation, expected 1: %+v", sheets.TotalSheets, *report)
+	}
+
+	list.AllowRotation = false
+	list.Items = []packing2d.Rectangle{{Width: 4, Height: 12}}
+	sheets = packing2d.NewSheetCollection(&list)
+	if err := sheets.PackAll(list.Items); err == nil {
+		t.Errorf("Expected an error for a rectangle too tall to fit unturned")
+	}
+}
@@ -0,0 +1,87 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+	"github.com/gnboorse/binpacking/packing2d"
+	"github.com/gnboorse/binpacking/packing3d"
+)
+
+// every kind of solution can be handled alike
+var _ = []binpacking.Solution{&binpacking.BinCollectionImpl{}, &binpacking.CuttingStock{},
+	&binpacking.VectorPacking{}, &packing2d.SheetCollection{}, &packing3d.ContainerCollection{}}
+
+// TestBoxPacking unit test checking that every algorithm gives a valid
+// packing within the weight limit and orientations allowed, with full support
+func TestBoxPacking(t *testing.T) {
+	r := rand.New(rand.NewSource(22))
+	upright := []packing3d.Orientation{packing3d.Upright, packing3d.UprightTurned}
+	for instance := 0; instance < 20; instance++ {
+		list := packing3d.PackingList{
+			Container: packing3d.Container{Width: 120, Depth: 80, Height: 100, MaxWeight: 500},
+			Support:   1}
+		var volume binpacking.Size
+		for i := 0; i < 60; i++ {
+			box := packing3d.Box{Width: binpacking.Size(r.Intn(50) + 10), Depth: binpacking.Size(r.Intn(40) + 10),
+				Height: binpacking.Size(r.Intn(40) + 10), Weight: binpacking.Size(r.Intn(40))}
+			if r.Intn(2) == 0 {
+				box.Orientations = upright
+			}
+			list.Items = append(list.Items, box)
+			volume += box.Volume()
+		}
+		bound := binpacking.Count((volume + 120*80*100 - 1) / (120 * 80 * 100))
+
+		for _, name := range packing3d.Algorithms() {
+			list.Algorithm = packing3d.GetAlgorithm(name)
+			containers := packing3d.NewContainerCollection(&list)
+			if err := containers.PackAll(list.Items); err != nil {
+				t.Fatal(err)
+			}
+			if report := packing3d.Verify(&list, containers); !report.Valid {
+				t.Errorf("Invalid %v solution: %+v", name, *report)
+			}
+			if containers.TotalContainers < bound {
+				t.Errorf("%v used %v containers, below the volume bound %v", name, containers.TotalContainers, bound)
+			}
+		}
+	}
+}
+
+// TestBoxPackingExact unit test checking that boxes filling a container
+// exactly are loaded into one, and that the weight limit opens another
+func TestBoxPackingExact(t *testing.T) {
+	list := packing3d.PackingList{Container: packing3d.Container{Width: 10, Depth: 10, Height: 10}}
+	for i := 0; i < 8; i++ {
+		list.Items = append(list.Items, packing3d.Box{Width: 5, Depth: 5, Height: 5, Weight: 10})
+	}
+	for _, name := range packing3d.Algorithms() {
+		for _, maxWeight := range []binpacking.Size{0, 40} {
+			list.Algorithm, list.MaxWeight = packing3d.GetAlgorithm(name), maxWeight
+			containers := packing3d.NewContainerCollection(&list)
+			if err := containers.PackAll(list.Items); err != nil {
+				t.Fatal(err)
+			}
+			expected := binpacking.Count(1)
+			if maxWeight > 0 {
+				expected = 2
+			}
+			if report := packing3d.Verify(&list, containers); !report.Valid || containers.TotalContainers != expected {
+				t.Errorf("%v used %v containers, expected %v: %+v", name, containers.TotalContainers, expected, *report)
+			}
+		}
+	}
+
+	// a box only fitting on its side is refused when it must stay upright
+	list = packing3d.PackingList{Container: packing3d.Container{Width: 30, Depth: 10, Height: 10},
+		Algorithm: packing3d.ExtremePoint, Items: []packing3d.Box{{Width: 5, Depth: 5, Height: 20}}}
+	if err := packing3d.NewContainerCollection(&list).PackAll(list.Items); err != nil {
+		t.Errorf("Unexpected error for a box which fits on its side: %v", err)
+	}
+	list.Items[0].Orientations = []packing3d.Orientation{packing3d.Upright, packing3d.UprightTurned}
+	if err := packing3d.NewContainerCollection(&list).PackAll(list.Items); err == nil {
+		t.Errorf("Expected an error for a box too tall to stand upright")
+	}
+}
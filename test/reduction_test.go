@@ -0,0 +1,56 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestReduce unit test checking that the reduction keeps an optimal solution reachable
+func TestReduce(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for instance := 0; instance < 200; instance++ {
+		items := make(binpacking.Items, 10)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(80) + 10)
+		}
+		fixed, remaining := binpacking.Reduce(items, 100)
+
+		packed := len(remaining)
+		for _, bin := range fixed {
+			packed += len(bin.Items)
+			for j, index := range bin.Indices {
+				if items[index] != bin.Items[j] {
+					t.Errorf("Fixed bin %v of %v records position %v", bin.Items, items, index)
+				}
+			}
+		}
+		if packed != len(items) {
+			t.Errorf("Reducing %v kept %v of %v items", items, packed, len(items))
+		}
+		if reduced := len(fixed) + optimalBinCount(remaining, 100, 0); reduced != optimalBinCount(items, 100, 0) {
+			t.Errorf("Reducing %v needs %v bins, optimal is %v", items, reduced, optimalBinCount(items, 100, 0))
+		}
+	}
+}
+
+// TestPackAllReduced unit test for packing with the reduction option on every algorithm
+func TestPackAllReduced(t *testing.T) {
+	items := binpacking.Items{70, 30, 60, 55, 45, 25, 20, 20, 15, 10, 90}
+	for _, name := range binpacking.Algorithms() {
+		if registeredByTests[name] {
+			continue // other tests' algorithms need not solve this
+		}
+		packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm(name), Items: items}
+		packingList.Reduce = true
+		problem := binpacking.NewBinCollection(&packingList)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		report := binpacking.Verify(&packingList, problem.(*binpacking.BinCollectionImpl))
+		if !report.Valid {
+			t.Errorf("%v produced an invalid solution after reduction: %+v", name, *report)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package binpackingtests
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// holds whether a session bin holds the item with the given ID, and of the
+// given size unless that is zero
+func holds(bin *binpacking.Bin, itemID int, item binpacking.Item) bool {
+	for j, id := range bin.Indices {
+		if id == itemID {
+			return item == 0 || bin.Items[j] == item
+		}
+	}
+	return false
+}
+
+// TestSession unit test checking that every session policy keeps each item
+// in the bin it reports, within capacity and the limit on items per bin,
+// with no empty bins, through random arrivals and departures
+func TestSession(t *testing.T) {
+	for _, name := range binpacking.SessionAlgorithms() {
+		for _, budget := range []int{0, 3} {
+			r := rand.New(rand.NewSource(25))
+			list := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm(name), MaxItems: 4,
+				Options: binpacking.Options{RepackBudget: budget}}
+			session, err := binpacking.NewSession(&list)
+			if err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			live := make([]int, 0)
+			inserted := 0
+			for operation := 0; operation < 300; operation++ {
+				if len(live) > 0 && r.Intn(5) < 2 {
+					k := r.Intn(len(live))
+					if err := session.Remove(live[k]); err != nil {
+						t.Fatalf("%v: %v", name, err)
+					}
+					live = append(live[:k], live[k+1:]...)
+				} else {
+					item := binpacking.Item(r.Intn(60) + 1)
+					binID, err := session.Insert(item)
+					if err != nil {
+						t.Fatalf("%v: %v", name, err)
+					}
+					if bin, ok := session.Bin(binID); !ok || !holds(bin, inserted, item) {
+						t.Errorf("%v with budget %v put item %v in bin %v, which does not hold it", name, budget, inserted, binID)
+					}
+					live = append(live, inserted)
+					inserted++
+				}
+			}
+
+			packing := session.Packing()
+			packed := 0
+			for _, bin := range packing.Bins {
+				if len(bin.Items) == 0 || bin.Usage > bin.Capacity || binpacking.Count(len(bin.Items)) > list.MaxItems {
+					t.Errorf("%v with budget %v left an invalid bin: %+v", name, budget, bin)
+				}
+				packed += len(bin.Items)
+			}
+			if packed != len(live) || packing.TotalBins != binpacking.Count(len(packing.Bins)) {
+				t.Errorf("%v with budget %v holds %v items in %v bins, expected %v items", name, budget, packed, packing.TotalBins, len(live))
+			}
+			for _, itemID := range live {
+				binID, ok := session.BinOf(itemID)
+				if bin, found := session.Bin(binID); !ok || !found || !holds(bin, itemID, 0) {
+					t.Errorf("%v with budget %v reports item %v in bin %v, which does not hold it", name, budget, itemID, binID)
+				}
+			}
+			held := binpacking.PackingList{Size: list.Size, MaxItems: list.MaxItems, Items: packing.GetInput()}
+			if report := binpacking.Verify(&held, packing); !report.Valid || len(held.Items) != len(live) {
+				t.Errorf("Invalid %v packing with budget %v: %+v", name, budget, *report)
+			}
+			metrics := session.Metrics
+			if metrics.Operations != 300 || len(metrics.BinsOverTime) != 300 || metrics.BinsOverTime[299] != packing.TotalBins {
+				t.Errorf("%v with budget %v has inconsistent metrics: %+v", name, budget, metrics)
+			}
+			for _, bins := range metrics.BinsOverTime {
+				if bins > metrics.PeakBins {
+					t.Errorf("%v with budget %v used %v bins, over the peak of %v", name, budget, bins, metrics.PeakBins)
+				}
+			}
+			if budget == 0 && metrics.Migrations != 0 {
+				t.Errorf("%v moved %v items without a repack budget", name, metrics.Migrations)
+			}
+		}
+	}
+}
+
+// TestSessionMetrics unit test checking that a long session samples the
+// bins in use at a growing interval, keeping the samples few
+func TestSessionMetrics(t *testing.T) {
+	list := binpacking.PackingList{Size: 10, Algorithm: binpacking.FirstFit}
+	session, err := binpacking.NewSession(&list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one item stays while another comes and goes, so the bins in use alternate between two and one
+	if _, err := session.Insert(8); err != nil {
+		t.Fatal(err)
+	}
+	bins := []binpacking.Count{1}
+	for operation := 1; operation < 5000; operation++ {
+		if operation%2 == 1 {
+			if _, err := session.Insert(8); err != nil {
+				t.Fatal(err)
+			}
+			bins = append(bins, 2)
+		} else {
+			if err := session.Remove(operation / 2); err != nil {
+				t.Fatal(err)
+			}
+			bins = append(bins, 1)
+		}
+	}
+	metrics := session.Metrics
+	if metrics.SampleInterval < 2 || len(metrics.BinsOverTime) > 1024 || len(metrics.BinsOverTime) != 5000/metrics.SampleInterval {
+		t.Fatalf("Unexpected sampling: %v samples at an interval of %v", len(metrics.BinsOverTime), metrics.SampleInterval)
+	}
+	for i, count := range metrics.BinsOverTime {
+		if expected := bins[(i+1)*metrics.SampleInterval-1]; count != expected {
+			t.Errorf("Expected %v bins after operation %v, got %v", expected, (i+1)*metrics.SampleInterval, count)
+		}
+	}
+}
+
+// TestSessionRepack unit test for emptying a bin by moving its items when one leaves
+func TestSessionRepack(t *testing.T) {
+	list := binpacking.PackingList{Size: 10, Algorithm: binpacking.FirstFit,
+		Options: binpacking.Options{RepackBudget: 1}}
+	session, err := binpacking.NewSession(&list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range []binpacking.Item{7, 7, 3, 3} {
+		if _, err := session.Insert(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// bins hold 7 and 3, then 7 and 3, so neither can be emptied until both 7s leave
+	for _, itemID := range []int{0, 1} {
+		if err := session.Remove(itemID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if binID, ok := session.BinOf(2); !ok || binID != 1 || session.Packing().TotalBins != 1 {
+		t.Errorf("Expected item 2 to move to bin 1, leaving one bin: %v", session.Packing())
+	}
+	if bin, ok := session.Bin(1); !ok || bin.Usage != 6 || !holds(bin, 2, 3) || !holds(bin, 3, 3) {
+		t.Errorf("Expected bin 1 to hold items 2 and 3: %+v", bin)
+	}
+	if _, ok := session.Bin(0); ok {
+		t.Errorf("Expected bin 0 to be closed")
+	}
+	metrics := session.Metrics
+	expected := []binpacking.Count{1, 2, 2, 2, 2, 1}
+	if metrics.PeakBins != 2 || metrics.Migrations != 1 || len(metrics.BinsOverTime) != len(expected) {
+		t.Fatalf("Unexpected metrics: %+v", metrics)
+	}
+	for i, bins := range expected {
+		if metrics.BinsOverTime[i] != bins {
+			t.Errorf("Expected %v bins after operation %v, got %v", bins, i, metrics.BinsOverTime[i])
+		}
+	}
+
+	var unknown *binpacking.UnknownItemError
+	if err := session.Remove(0); !errors.As(err, &unknown) || unknown.ID != 0 {
+		t.Errorf("Expected an UnknownItemError, got %v", err)
+	}
+	list.Algorithm = binpacking.FirstFitDecreasing
+	var unsupported *binpacking.UnknownAlgorithmError
+	if _, err := binpacking.NewSession(&list); !errors.As(err, &unsupported) {
+		t.Errorf("Expected an UnknownAlgorithmError, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package binpackingtests
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// cheapestPackingCost find the least cost of packing the items into bins of the given
+// types, with no limits on their number, by trying every partition of the items
+func cheapestPackingCost(items binpacking.Items, types binpacking.BinTypes) float64 {
+	best := math.Inf(1)
+	loads := make([]binpacking.Size, 0, len(items))
+	binCost := func(load binpacking.Size) float64 {
+		cost := math.Inf(1)
+		for _, binType := range types {
+			if binType.Capacity >= load {
+				cost = math.Min(cost, binType.Cost)
+			}
+		}
+		return cost
+	}
+	var assign func(i int)
+	assign = func(i int) {
+		if i == len(items) {
+			var cost float64
+			for _, load := range loads {
+				cost += binCost(load)
+			}
+			best = math.Min(best, cost)
+			return
+		}
+		for b := range loads {
+			loads[b] += binpacking.Size(items[i])
+			assign(i + 1)
+			loads[b] -= binpacking.Size(items[i])
+		}
+		loads = append(loads, binpacking.Size(items[i]))
+		assign(i + 1)
+		loads = loads[:len(loads)-1]
+	}
+	assign(0)
+	return best
+}
+
+// TestVariableSized unit test checking that the heuristics give valid packings
+// and the exact solver the cheapest one
+func TestVariableSized(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for instance := 0; instance < 50; instance++ {
+		types := binpacking.BinTypes{
+			{Name: "small", Capacity: 50, Cost: float64(r.Intn(20) + 30)},
+			{Name: "medium", Capacity: 80, Cost: float64(r.Intn(20) + 50)},
+			{Name: "large", Capacity: 100, Cost: float64(r.Intn(20) + 70)}}
+		items := make(binpacking.Items, 8)
+		for i := range items {
+			items[i] = binpacking.Item(r.Intn(60) + 5)
+		}
+		cheapest := cheapestPackingCost(items, types)
+
+		for _, algorithm := range []binpacking.Algorithm{binpacking.FirstFitDecreasing,
+			binpacking.BestFitDecreasing, binpacking.MartelloToth} {
+			packingList := binpacking.PackingList{Algorithm: algorithm, Items: items, BinTypes: types}
+			problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+			if err := problem.PackAll(items); err != nil {
+				t.Fatal(err)
+			}
+			if report := binpacking.Verify(&packingList, problem); !report.Valid {
+				t.Errorf("Invalid %v solution: %+v", algorithm, *report)
+			}
+			if problem.TotalCost < cheapest {
+				t.Errorf("%v cost %v for %v, below the cheapest %v", algorithm, problem.TotalCost, items, cheapest)
+			}
+			if algorithm == binpacking.MartelloToth && problem.TotalCost != cheapest {
+				t.Errorf("%v cost %v for %v, expected %v", algorithm, problem.TotalCost, items, cheapest)
+			}
+		}
+	}
+}
+
+// TestVariableSizedLimits unit test checking that bin type limits are
+// respected, and that too few bins is reported as infeasible
+func TestVariableSizedLimits(t *testing.T) {
+	items := binpacking.Items{60, 60, 60, 40, 40}
+	types := binpacking.BinTypes{{Capacity: 100, Cost: 10, Limit: 1}, {Capacity: 60, Cost: 9}}
+	packingList := binpacking.PackingList{Algorithm: binpacking.MartelloToth, Items: items, BinTypes: types}
+	problem := binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+	if err := problem.PackAll(items); err != nil {
+		t.Fatal(err)
+	}
+	if report := binpacking.Verify(&packingList, problem); !report.Valid {
+		t.Errorf("Invalid solution: %+v", *report)
+	}
+	if problem.TotalCost != 37 {
+		t.Errorf("Cost %v, expected 37", problem.TotalCost)
+	}
+
+	packingList.BinTypes = binpacking.BinTypes{{Capacity: 100, Cost: 10, Limit: 2}}
+	problem = binpacking.NewBinCollection(&packingList).(*binpacking.BinCollectionImpl)
+	if err := problem.PackAll(items); err != binpacking.ErrInfeasible {
+		t.Errorf("Expected ErrInfeasible, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package binpackingtests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestVectorPacking unit test checking that every algorithm and rule gives a
+// valid packing no better than the lower bound, and that one dimension
+// packs as the scalar algorithms do
+func TestVectorPacking(t *testing.T) {
+	r := rand.New(rand.NewSource(20))
+	for instance := 0; instance < 20; instance++ {
+		list := binpacking.VectorPackingList{Capacity: binpacking.Vector{64, 256, 1000}}
+		for i := 0; i < 40; i++ {
+			list.Items = append(list.Items, binpacking.Vector{
+				binpacking.Size(r.Intn(24) + 1), binpacking.Size(r.Intn(96)), binpacking.Size(r.Intn(300))})
+		}
+		bound := binpacking.CalculateVectorLowerBound(list.Items, list.Capacity)
+
+		for _, name := range binpacking.VectorAlgorithms() {
+			for _, rule := range binpacking.VectorRules() {
+				list.Algorithm, list.Ordering, list.Scoring = binpacking.GetAlgorithm(name), rule, rule
+				packing := binpacking.NewVectorPacking(&list)
+				if err := packing.PackAll(list.Items); err != nil {
+					t.Fatal(err)
+				}
+				if report := binpacking.VerifyVector(&list, packing); !report.Valid {
+					t.Errorf("Invalid %v %v solution: %+v", name, rule, *report)
+				}
+				if packing.TotalBins < bound {
+					t.Errorf("%v %v used %v bins, below the lower bound %v", name, rule, packing.TotalBins, bound)
+				}
+			}
+		}
+	}
+
+	items := binpacking.Items{42, 63, 67, 57, 44, 61, 78, 20, 36, 3, 13, 73, 39, 25}
+	list := binpacking.VectorPackingList{Capacity: binpacking.Vector{100}, Algorithm: binpacking.FirstFitDecreasing}
+	for _, item := range items {
+		list.Items = append(list.Items, binpacking.Vector{binpacking.Size(item)})
+	}
+	packing := binpacking.NewVectorPacking(&list)
+	if err := packing.PackAll(list.Items); err != nil {
+		t.Fatal(err)
+	}
+	packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.FirstFitDecreasing}
+	problem := binpacking.NewBinCollection(&packingList)
+	if err := problem.PackAll(items); err != nil {
+		t.Fatal(err)
+	}
+	if packing.TotalBins != problem.GetTotalBins() {
+		t.Errorf("Packed %v bins in one dimension, expected %v", packing.TotalBins, problem.GetTotalBins())
+	}
+}
+
+// TestVectorPackingCustomRules unit test checking that custom rules are used
+func TestVectorPackingCustomRules(t *testing.T) {
+	list := binpacking.VectorPackingList{Capacity: binpacking.Vector{10, 10}, Algorithm: binpacking.BestFitDecreasing,
+		Items: []binpacking.Vector{{1, 9}, {9, 1}, {5, 5}}}
+	packing := binpacking.NewVectorPacking(&list)
+	// the first dimension alone, smallest first
+	packing.ItemOrdering = binpacking.ItemOrderingFunc(func(item, capacity binpacking.Vector) float64 {
+		return -float64(item[0])
+	})
+	// the most room on the second dimension
+	packing.BinScoring = binpacking.BinScoringFunc(func(item, remaining, capacity binpacking.Vector) float64 {
+		return float64(remaining[1])
+	})
+	if err := packing.PackAll(list.Items); err != nil {
+		t.Fatal(err)
+	}
+	if packing.TotalBins != 2 || packing.Bins[0].Indices[0] != 0 || packing.Bins[0].Indices[1] != 1 {
+		t.Errorf("Unexpected packing: %v", packing)
+	}
+
+	list.Ordering = "Volume"
+	packing = binpacking.NewVectorPacking(&list)
+	if err := packing.PackAll(list.Items); err == nil {
+		t.Errorf("Expected an error for an unknown ordering")
+	}
+}
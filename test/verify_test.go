@@ -0,0 +1,42 @@
+package binpackingtests
+
+import (
+	"testing"
+
+	"github.com/gnboorse/binpacking"
+)
+
+// TestVerify unit test for verifying solutions of every algorithm
+func TestVerify(t *testing.T) {
+	// 60 and 40 exercise the MFFD step that pairs the first B item with an A bin
+	items := binpacking.Items{60, 40, 35, 30, 20, 20, 15, 10, 5, 5}
+	for _, name := range binpacking.Algorithms() {
+		if registeredByTests[name] {
+			continue // other tests' algorithms need not solve this
+		}
+		packingList := binpacking.PackingList{Size: 100, Algorithm: binpacking.GetAlgorithm(name), Items: items}
+		problem := binpacking.NewBinCollection(&packingList)
+		if err := problem.PackAll(items); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		report := binpacking.Verify(&packingList, problem.(*binpacking.BinCollectionImpl))
+		if !report.Valid {
+			t.Errorf("%v produced an invalid solution: %+v", name, *report)
+		}
+	}
+}
+
+// TestVerifyInvalid unit test for detecting broken solutions
+func TestVerifyInvalid(t *testing.T) {
+	packingList := binpacking.PackingList{Size: 10, Items: binpacking.Items{6, 5, 4}}
+	solution := &binpacking.BinCollectionImpl{BinCapacity: 10, TotalBins: 1}
+	bin := binpacking.NewBin(10)
+	bin.Pack(6)
+	bin.Pack(6)
+	solution.Bins = append(solution.Bins, bin)
+
+	report := binpacking.Verify(&packingList, solution)
+	if report.Valid || len(report.OverfullBins) != 1 || len(report.ExtraItems) != 1 || len(report.MissingItems) != 2 {
+		t.Errorf("Unexpected report: %+v", *report)
+	}
+}
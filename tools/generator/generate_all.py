@@ -18,7 +18,21 @@ ALGORITHMS = [
     "BestFitDecreasing",
     "PackingConstraint",
     "BinCompletion",
-    "ModifiedFirstFitDecreasing"
+    "ModifiedFirstFitDecreasing",
+    "NextFitDecreasing",
+    "WorstFit",
+    "WorstFitDecreasing",
+    "AlmostWorstFit",
+    "Harmonic",
+    "RefinedHarmonic",
+    "ModifiedHarmonic",
+    "MartelloToth",
+    "HybridGroupingGenetic",
+    "SimulatedAnnealing",
+    "TabuSearch",
+    "ColumnGeneration",
+    "FirstFitDecreasing+LS",
+    "BestFitDecreasing+LS"
 ]
 
 DUPLICATES = 10000
@@ -5,8 +5,10 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gnboorse/binpacking"
 )
@@ -17,18 +19,38 @@ func main() {
 	itemMaxSize := flag.Int("max", 100, "The maximum size for a bin")
 	itemCenter := flag.Int("center", 50, "Center of concentrated values")
 	itemVariability := flag.Int("variability", int(binpacking.LowVariability), "Measure of the variability of values (should be 1, 2, or 3)")
-	algorithm := flag.String("algorithm", "NextFit", "The name of the algorithm to use when solving the problem")
+	algorithm := flag.String("algorithm", "NextFit", "The name of the algorithm to use when solving the problem. One of: "+strings.Join(binpacking.Algorithms(), ", "))
 	duplicates := flag.Int("dups", 1, "How many of this kind of problem to generate")
 	outputDirectory := flag.String("output", "json", "Directory to put files in.")
+	k := flag.Int("k", 0, "Number of size classes for the Harmonic algorithms (0 for the default)")
+	reduce := flag.Bool("reduce", false, "Fix bins with the MTRP reduction before packing")
+	seed := flag.Int64("seed", 0, "Seed for the random choices of the metaheuristics")
+	iterations := flag.Int("iterations", 0, "Iterations of simulated annealing and tabu search (0 for the default)")
+	generations := flag.Int("generations", 0, "Generations of the genetic algorithm (0 for the default)")
+	population := flag.Int("population", 0, "Population of the genetic algorithm (0 for the default)")
+	timeLimit := flag.Duration("timelimit", 0, "Time limit for the metaheuristics (0 for none)")
+	log.SetFlags(0)
 	flag.Parse()
+	if binpacking.GetAlgorithm(*algorithm).Packer() == nil {
+		log.Fatalf("unknown algorithm %q, expected one of: %s",
+			*algorithm, strings.Join(binpacking.Algorithms(), ", "))
+	}
+	// items are generated strictly between zero and the maximum size
+	if *itemCount <= 0 || *itemMaxSize <= 1 {
+		log.Fatalf("invalid problem size: count %v, max %v", *itemCount, *itemMaxSize)
+	}
+	if *itemCenter <= 0 || *itemCenter >= *itemMaxSize {
+		log.Fatalf("center %v must be between 0 and max %v", *itemCenter, *itemMaxSize)
+	}
+	if *itemVariability < int(binpacking.HighVariability) || *itemVariability > int(binpacking.LowVariability) {
+		log.Fatalf("variability %v must be 1, 2, or 3", *itemVariability)
+	}
 	for i := 0; i < *duplicates; i++ {
 		// randomly generate items based on params provided
 		items := binpacking.GenerateItems(*itemCount, *itemMaxSize, *itemCenter, binpacking.Variability(*itemVariability))
 
-		// calculate lower bound for most optimal solution
-		tmp := make(binpacking.Items, len(items))
-		copy(tmp, items)
-		lowerBound := binpacking.CalculateLowerBound(tmp, binpacking.Size(*itemMaxSize))
+		// calculate the tightest lower bound for most optimal solution
+		lowerBound := binpacking.BestLowerBound(items, binpacking.Size(*itemMaxSize))
 
 		// create packing list
 		packingList := binpacking.PackingList{
@@ -38,20 +60,30 @@ func main() {
 			Variability: binpacking.Variability(*itemVariability),
 			Algorithm:   binpacking.GetAlgorithm(*algorithm),
 			Items:       items,
-			LowerBound:  lowerBound}
+			LowerBound:  lowerBound,
+			Options: binpacking.Options{
+				K:           *k,
+				Reduce:      *reduce,
+				Seed:        *seed,
+				Iterations:  *iterations,
+				Generations: *generations,
+				Population:  *population,
+				TimeLimit:   *timeLimit}}
 
 		jsonValue, err := json.MarshalIndent(packingList, "", "  ")
 		if err != nil {
-			panic(err)
+			log.Fatalf("unable to encode problem: %v", err)
 		}
 		newpath := filepath.Join(".", *outputDirectory)
-		os.MkdirAll(newpath, os.ModePerm)
+		if err := os.MkdirAll(newpath, os.ModePerm); err != nil {
+			log.Fatalf("unable to create output directory: %v", err)
+		}
 		filename := fmt.Sprintf("%v/binpacking%v_%vcount_%vmax_%vcenter_%vvariability_%s.json",
 			*outputDirectory, i, *itemCount, *itemMaxSize,
 			*itemCenter, *itemVariability, packingList.Algorithm)
 		err = ioutil.WriteFile(filename, jsonValue, 0644)
 		if err != nil {
-			panic(err)
+			log.Fatalf("unable to write problem: %v", err)
 		}
 
 	}
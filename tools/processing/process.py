@@ -70,7 +70,7 @@ def main():
                                       Column('center', Integer),
                                       Column('variability', Integer),
                                       Column('lower_bound', Integer),
-                                      Column('algorithm', Integer),
+                                      Column('algorithm', String),
                                       Column('solution_bin_count', Integer),
                                       Column('solution_time', Integer),
                                       Column('solution_optimality', Float))
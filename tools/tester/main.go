@@ -1,45 +1,269 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"io/ioutil"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/gnboorse/binpacking"
+	"github.com/gnboorse/binpacking/packing2d"
+	"github.com/gnboorse/binpacking/packing3d"
 )
 
+// result output of a single run, including the verifier's verdict
+type result struct {
+	*binpacking.BinCollectionImpl
+	Verification *binpacking.VerificationReport `json:"verification"`
+}
+
 func main() {
 	// main entrypoint for running bin packing problems
+	log.SetFlags(0)
 	inputFile := flag.String("file", "input.json", "File to run.")
 	outputFile := flag.String("output", "output.json", "Output file for results.")
+	algorithm := flag.String("algorithm", "", "Algorithm to run instead of the one named in the file. One of: "+strings.Join(binpacking.Algorithms(), ", "))
+	timeout := flag.Duration("timeout", 0, "Give up on packing after this long, e.g. 30s. Exact solvers keep their best packing so far. Zero means no limit.")
 	flag.Parse()
 	b, err := ioutil.ReadFile(*inputFile)
 	if err != nil {
-		panic(err)
+		log.Fatalf("unable to read input: %v", err)
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	// problems listing demands are cutting stock problems
+	var cuttingStockList binpacking.CuttingStockList
+	if err := json.Unmarshal(b, &cuttingStockList); err == nil && cuttingStockList.Demands != nil {
+		if *algorithm != "" {
+			cuttingStockList.Algorithm = binpacking.GetAlgorithm(*algorithm)
+		}
+		runCuttingStock(ctx, &cuttingStockList, *inputFile, *outputFile)
+		return
+	}
+
+	// problems whose capacity is a list of sizes are vector packing problems
+	var vectorList binpacking.VectorPackingList
+	if err := json.Unmarshal(b, &vectorList); err == nil && len(vectorList.Capacity) > 0 {
+		if *algorithm != "" {
+			vectorList.Algorithm = binpacking.GetAlgorithm(*algorithm)
+		}
+		runVectorPacking(ctx, &vectorList, *inputFile, *outputFile)
+		return
+	}
+
+	// problems giving the container depth are box packing problems
+	var containerList packing3d.PackingList
+	if err := json.Unmarshal(b, &containerList); err == nil && containerList.Depth > 0 {
+		if *algorithm != "" {
+			containerList.Algorithm = packing3d.GetAlgorithm(*algorithm)
+		}
+		runBoxPacking(ctx, &containerList, *inputFile, *outputFile)
+		return
+	}
+
+	// problems giving the sheet width are rectangle packing problems
+	var sheetList packing2d.PackingList
+	if err := json.Unmarshal(b, &sheetList); err == nil && sheetList.Width > 0 {
+		if *algorithm != "" {
+			sheetList.Algorithm = packing2d.GetAlgorithm(*algorithm)
+		}
+		runRectanglePacking(ctx, &sheetList, *inputFile, *outputFile)
+		return
 	}
 
 	var packingList binpacking.PackingList
 	err = json.Unmarshal(b, &packingList)
 	if err != nil {
-		panic(err)
+		log.Fatalf("unable to parse %s: %v", *inputFile, err)
+	}
+	if *algorithm != "" {
+		packingList.Algorithm = binpacking.GetAlgorithm(*algorithm)
 	}
 
 	problem := binpacking.NewBinCollection(&packingList)
 
 	start := time.Now()
 	// time how long it takes to pack
-	problem.PackAll(packingList.Items)
+	err = problem.PackAllContext(ctx, packingList.Items)
 	elapsed := time.Since(start)
+	var unknownAlgorithm *binpacking.UnknownAlgorithmError
+	if errors.As(err, &unknownAlgorithm) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(binpacking.Algorithms(), ", "))
+	} else if err != nil {
+		log.Fatalf("unable to pack %s: %v", *inputFile, err)
+	}
 	// set duration of run
 	problem.SetTime(elapsed.Nanoseconds())
 
-	jsonValue, err := json.MarshalIndent(problem, "", "  ")
+	solution := problem.(*binpacking.BinCollectionImpl)
+	report := binpacking.Verify(&packingList, solution)
+	if !report.Valid {
+		log.Printf("invalid solution for %s: %+v", *inputFile, *report)
+	}
+
+	jsonValue, err := json.MarshalIndent(result{solution, report}, "", "  ")
 	if err != nil {
-		panic(err)
+		log.Fatalf("unable to encode results: %v", err)
 	}
 	err = ioutil.WriteFile(*outputFile, jsonValue, 0644)
 	if err != nil {
-		panic(err)
+		log.Fatalf("unable to write results: %v", err)
+	}
+}
+
+// stockResult output of a single cutting stock run, including the verifier's verdict
+type stockResult struct {
+	*binpacking.CuttingStock
+	Verification *binpacking.VerificationReport `json:"verification"`
+}
+
+// runCuttingStock solve a cutting stock problem and write out its patterns
+func runCuttingStock(ctx context.Context, list *binpacking.CuttingStockList, inputFile, outputFile string) {
+	problem := binpacking.NewCuttingStock(list)
+
+	start := time.Now()
+	err := problem.PackAllContext(ctx, list.Demands)
+	elapsed := time.Since(start)
+	var unknownAlgorithm *binpacking.UnknownAlgorithmError
+	if errors.As(err, &unknownAlgorithm) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(binpacking.CuttingStockAlgorithms(), ", "))
+	} else if err != nil {
+		log.Fatalf("unable to pack %s: %v", inputFile, err)
+	}
+	problem.SetTime(elapsed.Nanoseconds())
+
+	report := binpacking.VerifyCuttingStock(list, problem)
+	if !report.Valid {
+		log.Printf("invalid solution for %s: %+v", inputFile, *report)
+	}
+
+	jsonValue, err := json.MarshalIndent(stockResult{problem, report}, "", "  ")
+	if err != nil {
+		log.Fatalf("unable to encode results: %v", err)
+	}
+	err = ioutil.WriteFile(outputFile, jsonValue, 0644)
+	if err != nil {
+		log.Fatalf("unable to write results: %v", err)
+	}
+}
+
+// vectorResult output of a single vector packing run, including the verifier's verdict
+type vectorResult struct {
+	*binpacking.VectorPacking
+	Verification *binpacking.VerificationReport `json:"verification"`
+}
+
+// runVectorPacking solve a vector packing problem and write out its bins
+func runVectorPacking(ctx context.Context, list *binpacking.VectorPackingList, inputFile, outputFile string) {
+	problem := binpacking.NewVectorPacking(list)
+
+	start := time.Now()
+	err := problem.PackAllContext(ctx, list.Items)
+	elapsed := time.Since(start)
+	var unknownAlgorithm *binpacking.UnknownAlgorithmError
+	var unknownRule *binpacking.UnknownRuleError
+	if errors.As(err, &unknownAlgorithm) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(binpacking.VectorAlgorithms(), ", "))
+	} else if errors.As(err, &unknownRule) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(binpacking.VectorRules(), ", "))
+	} else if err != nil {
+		log.Fatalf("unable to pack %s: %v", inputFile, err)
+	}
+	problem.SetTime(elapsed.Nanoseconds())
+
+	report := binpacking.VerifyVector(list, problem)
+	if !report.Valid {
+		log.Printf("invalid solution for %s: %+v", inputFile, *report)
+	}
+
+	jsonValue, err := json.MarshalIndent(vectorResult{problem, report}, "", "  ")
+	if err != nil {
+		log.Fatalf("unable to encode results: %v", err)
+	}
+	err = ioutil.WriteFile(outputFile, jsonValue, 0644)
+	if err != nil {
+		log.Fatalf("unable to write results: %v", err)
+	}
+}
+
+// sheetResult output of a single rectangle packing run, including the verifier's verdict
+type sheetResult struct {
+	*packing2d.SheetCollection
+	Verification *binpacking.VerificationReport `json:"verification"`
+}
+
+// runRectanglePacking solve a rectangle packing problem and write out its sheets
+func runRectanglePacking(ctx context.Context, list *packing2d.PackingList, inputFile, outputFile string) {
+	problem := packing2d.NewSheetCollection(list)
+
+	start := time.Now()
+	err := problem.PackAllContext(ctx, list.Items)
+	elapsed := time.Since(start)
+	var unknownAlgorithm *packing2d.UnknownAlgorithmError
+	if errors.As(err, &unknownAlgorithm) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(packing2d.Algorithms(), ", "))
+	} else if err != nil {
+		log.Fatalf("unable to pack %s: %v", inputFile, err)
+	}
+	problem.SetTime(elapsed.Nanoseconds())
+
+	report := packing2d.Verify(list, problem)
+	if !report.Valid {
+		log.Printf("invalid solution for %s: %+v", inputFile, *report)
+	}
+
+	jsonValue, err := json.MarshalIndent(sheetResult{problem, report}, "", "  ")
+	if err != nil {
+		log.Fatalf("unable to encode results: %v", err)
+	}
+	err = ioutil.WriteFile(outputFile, jsonValue, 0644)
+	if err != nil {
+		log.Fatalf("unable to write results: %v", err)
+	}
+}
+
+// containerResult output of a single box packing run, including the verifier's verdict
+type containerResult struct {
+	*packing3d.ContainerCollection
+	Verification *binpacking.VerificationReport `json:"verification"`
+}
+
+// runBoxPacking solve a box packing problem and write out its containers
+func runBoxPacking(ctx context.Context, list *packing3d.PackingList, inputFile, outputFile string) {
+	problem := packing3d.NewContainerCollection(list)
+
+	start := time.Now()
+	err := problem.PackAllContext(ctx, list.Items)
+	elapsed := time.Since(start)
+	var unknownAlgorithm *packing3d.UnknownAlgorithmError
+	if errors.As(err, &unknownAlgorithm) {
+		log.Fatalf("%v, expected one of: %s", err, strings.Join(packing3d.Algorithms(), ", "))
+	} else if err != nil {
+		log.Fatalf("unable to pack %s: %v", inputFile, err)
+	}
+	problem.SetTime(elapsed.Nanoseconds())
+
+	report := packing3d.Verify(list, problem)
+	if !report.Valid {
+		log.Printf("invalid solution for %s: %+v", inputFile, *report)
+	}
+
+	jsonValue, err := json.MarshalIndent(containerResult{problem, report}, "", "  ")
+	if err != nil {
+		log.Fatalf("unable to encode results: %v", err)
+	}
+	err = ioutil.WriteFile(outputFile, jsonValue, 0644)
+	if err != nil {
+		log.Fatalf("unable to write results: %v", err)
 	}
 }
@@ -15,6 +15,8 @@ def main():
         description='Run all instances of a given algorithm.')
     parser.add_argument(
         '--algorithm', '-a', help="The algorithm to run all instances for.")
+    parser.add_argument(
+        '--timeout', '-t', default='0', help="Time limit per instance, e.g. 30s.")
     args = parser.parse_args()
 
     # create results directory
@@ -40,7 +42,7 @@ def main():
                     input_json = f'{args.algorithm}/{filename}.json'
                     output_json = f'{dirname}/{filename}_results.json'
 
-                    bashCommand = f'./tester -file={input_json} -output={output_json}'
+                    bashCommand = f'./tester -file={input_json} -output={output_json} -timeout={args.timeout}'
                     print(f'Running bash command: {bashCommand}')
                     process = subprocess.Popen(
                         bashCommand.split(), stdout=subprocess.PIPE)
@@ -15,6 +15,8 @@ def main():
         description='Run all instances of a given algorithm.')
     parser.add_argument(
         '--file', '-f', help="The directory containing files.")
+    parser.add_argument(
+        '--timeout', '-t', default='0', help="Time limit per instance, e.g. 30s.")
     args = parser.parse_args()
 
     # create results directory
@@ -30,7 +32,7 @@ def main():
 
     for input_json in tqdm(os.listdir(args.file)):
         output_json_filename = f'{os.path.splitext(input_json)[0]}_results.json'
-        bashCommand = f'./tester -file={os.path.join(args.file,input_json)} -output={os.path.join(results_dirname, output_json_filename)}'
+        bashCommand = f'./tester -file={os.path.join(args.file,input_json)} -output={os.path.join(results_dirname, output_json_filename)} -timeout={args.timeout}'
         # print(f'Running bash command: {bashCommand}')
         process = subprocess.Popen(
             bashCommand.split(), stdout=subprocess.PIPE)
@@ -0,0 +1,188 @@
+package binpacking
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// variableSearch state of a branch-and-bound search for the cheapest
+// packing into bins of several types
+type variableSearch struct {
+	ctx         context.Context
+	types       BinTypes
+	maxItems    Count // most items in a bin, unlimited when zero
+	items       Items
+	order       []int   // positions of the items, largest first
+	after       []Size  // total size of the items from each point in the order on
+	unitCost    float64 // least cost of a unit of capacity over all types
+	bins        []typedBin
+	used        []Count // bins of each type open
+	cost        float64
+	best        []typedBin
+	bestCost    float64
+	floor       float64 // lower bound on the cost of the whole problem
+	nodes       int64
+	interrupted bool // the context was done before the search finished
+}
+
+// packVariableExact pack the items at the least total cost by branch and
+// bound. Items are placed largest first, each into every open bin it fits,
+// fullest first and skipping bins of the same type and load, then into a new
+// bin of every type it fits and which is available, cheapest first. Each node
+// is bounded by the cost so far plus the items left which do not fit the room
+// in the open bins with a slot left, at the least cost per unit of capacity,
+// and by the cost of the bins the items left need when bins hold at most
+// MaxItems items and the open bins lack the slots for them. The cheaper
+// packing of the first fit and best fit heuristics is the starting point.
+// If the context or time limit is done before the search finishes, the
+// cheapest packing found is used and the status is set to NotProvenOptimal.
+func (binCollection *BinCollectionImpl) packVariableExact(ctx context.Context, items Items) error {
+	ctx, cancel := binCollection.withTimeLimit(ctx)
+	defer cancel()
+	types := binCollection.BinTypes
+	search := &variableSearch{
+		ctx:      ctx,
+		types:    types,
+		maxItems: binCollection.MaxItems,
+		items:    items,
+		order:    items.decreasingOrder(),
+		unitCost: math.Inf(1),
+		used:     make([]Count, len(types)),
+		bestCost: math.Inf(1)}
+	for _, binType := range types {
+		search.unitCost = math.Min(search.unitCost, binType.Cost/float64(binType.Capacity))
+	}
+	search.after = make([]Size, len(search.order)+1)
+	for k := len(search.order) - 1; k >= 0; k-- {
+		search.after[k] = search.after[k+1] + Size(items[search.order[k]])
+	}
+	search.floor = float64(search.after[0]) * search.unitCost
+	for _, best := range []bool{false, true} {
+		if bins := cheapestTyped(items, search.order, types, best, search.maxItems); bins != nil && typedCost(bins, types) < search.bestCost {
+			search.best, search.bestCost = bins, typedCost(bins, types)
+		}
+	}
+
+	binCollection.Status = Optimal
+	if search.bestCost > search.floor+simplexEpsilon {
+		search.branch(0)
+		if search.interrupted {
+			binCollection.Status = NotProvenOptimal
+		}
+	}
+	binCollection.Nodes = search.nodes
+	if search.best == nil {
+		return ErrInfeasible
+	}
+	binCollection.setTypedBins(items, search.best)
+	return nil
+}
+
+// branch place the item at the given point in the order in every undominated way
+func (search *variableSearch) branch(k int) {
+	search.nodes++
+	if search.nodes%1024 == 0 && search.ctx.Err() != nil {
+		search.interrupted = true
+	}
+	if search.interrupted || search.bestCost <= search.floor+simplexEpsilon {
+		return
+	}
+	var room Size
+	slots := 0
+	for _, bin := range search.bins {
+		if bin.hasSlot(search.maxItems) {
+			room += search.types[bin.kind].Capacity - bin.load
+			slots += int(search.maxItems) - len(bin.items)
+		}
+	}
+	bound := search.cost
+	if left := search.after[k] - room; left > 0 {
+		bound += float64(left) * search.unitCost
+	}
+	if left := len(search.order) - k - slots; search.maxItems > 0 && left > 0 {
+		// each new bin, of whatever type, holds at most maxItems of the items left
+		counted := search.cost + float64(ceilDivide(Size(left), Size(search.maxItems)))*search.cheapestCost()
+		bound = math.Max(bound, counted)
+	}
+	if bound >= search.bestCost-simplexEpsilon {
+		return
+	}
+	if k == len(search.order) {
+		search.record()
+		return
+	}
+
+	position := search.order[k]
+	size := Size(search.items[position])
+	candidates := make([]int, 0, len(search.bins))
+	for b, bin := range search.bins {
+		if search.types[bin.kind].Capacity-bin.load >= size && bin.hasSlot(search.maxItems) {
+			candidates = append(candidates, b)
+		}
+	}
+	// fullest first, and bins of the same type and load only once
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return search.remainingRoom(candidates[a]) < search.remainingRoom(candidates[b])
+	})
+	for c, b := range candidates {
+		if c > 0 && search.sameBin(b, candidates[c-1]) {
+			continue
+		}
+		bin := &search.bins[b]
+		bin.load += size
+		bin.items = append(bin.items, position)
+		search.branch(k + 1)
+		bin = &search.bins[b]
+		bin.load -= size
+		bin.items = bin.items[:len(bin.items)-1]
+	}
+
+	kinds := make([]int, 0, len(search.types))
+	for kind, binType := range search.types {
+		if binType.Capacity >= size && search.types.available(kind, search.used) {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.SliceStable(kinds, func(a, b int) bool { return search.types[kinds[a]].Cost < search.types[kinds[b]].Cost })
+	for _, kind := range kinds {
+		search.used[kind]++
+		search.cost += search.types[kind].Cost
+		search.bins = append(search.bins, typedBin{kind: kind, load: size, items: []int{position}})
+		search.branch(k + 1)
+		search.bins = search.bins[:len(search.bins)-1]
+		search.cost -= search.types[kind].Cost
+		search.used[kind]--
+	}
+}
+
+// remainingRoom the room left in an open bin
+func (search *variableSearch) remainingRoom(b int) Size {
+	return search.types[search.bins[b].kind].Capacity - search.bins[b].load
+}
+
+// sameBin whether two open bins are of the same type, load and number of items, so
+// placing an item in either leads to the same packings
+func (search *variableSearch) sameBin(a, b int) bool {
+	return search.bins[a].kind == search.bins[b].kind && search.bins[a].load == search.bins[b].load &&
+		len(search.bins[a].items) == len(search.bins[b].items)
+}
+
+// cheapestCost the least cost of a bin of any type
+func (search *variableSearch) cheapestCost() float64 {
+	cheapest := math.Inf(1)
+	for _, binType := range search.types {
+		cheapest = math.Min(cheapest, binType.Cost)
+	}
+	return cheapest
+}
+
+// record keep the bins packed so far as the cheapest packing
+func (search *variableSearch) record() {
+	search.best = make([]typedBin, len(search.bins))
+	for b, bin := range search.bins {
+		search.best[b] = typedBin{kind: bin.kind, load: bin.load, items: make([]int, len(bin.items))}
+		copy(search.best[b].items, bin.items)
+	}
+	search.bestCost = search.cost
+}
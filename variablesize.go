@@ -0,0 +1,237 @@
+package binpacking
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// BinType a kind of bin to choose from when packing bins of several sizes
+type BinType struct {
+	// Name identifies the type to the caller, e.g. "26t truck"
+	Name     string  `json:"name,omitempty"`
+	Capacity Size    `json:"capacity"`
+	Cost     float64 `json:"cost"`
+	// Limit the most bins of this type available, unlimited when zero
+	Limit Count `json:"limit,omitempty"`
+}
+
+// BinTypes collection type for BinType
+type BinTypes []BinType
+
+// available whether another bin of the given type can be used
+func (types BinTypes) available(kind int, used []Count) bool {
+	return types[kind].Limit == 0 || used[kind] < types[kind].Limit
+}
+
+// cheapest the cheapest type with room for the given size which can still
+// be used, preferring the larger of equally cheap types, or -1 if none can
+func (types BinTypes) cheapest(size Size, used []Count) int {
+	best := -1
+	for kind, binType := range types {
+		if binType.Capacity < size || !types.available(kind, used) {
+			continue
+		}
+		if best < 0 || binType.Cost < types[best].Cost ||
+			(binType.Cost == types[best].Cost && binType.Capacity > types[best].Capacity) {
+			best = kind
+		}
+	}
+	return best
+}
+
+// variableSizedPackers the algorithms which can pack bins of several types
+var variableSizedPackers = map[Algorithm]func(*BinCollectionImpl, context.Context, Items) error{
+	FirstFitDecreasing: func(binCollection *BinCollectionImpl, ctx context.Context, items Items) error {
+		return binCollection.packTypedDecreasing(items, false)
+	},
+	BestFitDecreasing: func(binCollection *BinCollectionImpl, ctx context.Context, items Items) error {
+		return binCollection.packTypedDecreasing(items, true)
+	},
+	MartelloToth: (*BinCollectionImpl).packVariableExact,
+}
+
+// VariableSizedAlgorithms get the names of the algorithms which can pack bins of several types
+func VariableSizedAlgorithms() []string {
+	algorithms := make([]Algorithm, 0, len(variableSizedPackers))
+	for algorithm := range variableSizedPackers {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool { return algorithms[i] < algorithms[j] })
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = algorithm.String()
+	}
+	return names
+}
+
+// packVariableSized pack the items into bins of the collection's types at
+// the least total cost. BinCapacity is set to the largest capacity, which
+// every item must fit, and bins of every type hold at most MaxItems items
+// when that is set. Returns ErrInfeasible if the limits on the number of
+// bins of each type leave too few bins for the items. No algorithm keeps
+// conflicting items apart in bins of several types, so any valid Conflicts
+// give an UnknownAlgorithmError.
+func (binCollection *BinCollectionImpl) packVariableSized(ctx context.Context, items Items) error {
+	pack, ok := variableSizedPackers[binCollection.Algorithm]
+	if !ok {
+		return &UnknownAlgorithmError{Algorithm: binCollection.Algorithm}
+	}
+	if binCollection.MaxItems < 0 {
+		return &InvalidMaxItemsError{MaxItems: binCollection.MaxItems}
+	}
+	binCollection.BinCapacity = 0
+	for i, binType := range binCollection.BinTypes {
+		if binType.Capacity <= 0 || binType.Cost < 0 || binType.Limit < 0 {
+			return &InvalidBinTypeError{Index: i, BinType: binType}
+		}
+		if binType.Capacity > binCollection.BinCapacity {
+			binCollection.BinCapacity = binType.Capacity
+		}
+	}
+	for i, item := range items {
+		if err := validateItem(i, item, binCollection.BinCapacity); err != nil {
+			return err
+		}
+	}
+	if err := binCollection.Conflicts.validate(len(items)); err != nil {
+		return err
+	}
+	if len(binCollection.Conflicts) > 0 {
+		return &UnknownAlgorithmError{Algorithm: binCollection.Algorithm}
+	}
+	binCollection.input = make(Items, len(items))
+	copy(binCollection.input, items)
+	binCollection.Bins = make(Bins, 0)
+	binCollection.TotalBins = 0
+	if err := pack(binCollection, ctx, binCollection.input); err != nil {
+		return err
+	}
+	binCollection.TotalCost = 0
+	for _, bin := range binCollection.Bins {
+		binCollection.TotalCost += binCollection.BinTypes[bin.Type].Cost
+	}
+	if binCollection.Status == Unsolved {
+		binCollection.Status = Feasible
+	}
+	return nil
+}
+
+// typedBin a bin being packed with items of the given input positions
+type typedBin struct {
+	kind  int
+	load  Size
+	items []int
+}
+
+// hasSlot whether the bin can take another item without going over maxItems
+func (bin typedBin) hasSlot(maxItems Count) bool {
+	return maxItems <= 0 || Count(len(bin.items)) < maxItems
+}
+
+// packTypedDecreasing pack the items largest first with first fit, or best
+// fit if best is set, opening bins of one type for as long as they fit the
+// items and are available, and otherwise of the cheapest type which fits and
+// is available. Each bin is then given the cheapest type it fits in, so the
+// bins may be smaller than those opened. This is tried with each type in turn
+// as the one preferred, and the cheapest packing kept, as in Kang and Park's
+// iterative first fit decreasing.
+func (binCollection *BinCollectionImpl) packTypedDecreasing(items Items, best bool) error {
+	bins := cheapestTyped(items, items.decreasingOrder(), binCollection.BinTypes, best, binCollection.MaxItems)
+	if bins == nil {
+		return ErrInfeasible
+	}
+	binCollection.setTypedBins(items, bins)
+	return nil
+}
+
+// cheapestTyped the cheapest packing by fitTyped over each preferred
+// type, or nil if the limits leave too few bins for all of them
+func cheapestTyped(items Items, order []int, types BinTypes, best bool, maxItems Count) []typedBin {
+	var cheapest []typedBin
+	cheapestCost := math.Inf(1)
+	for preferred := range types {
+		bins, ok := fitTyped(items, order, types, preferred, best, maxItems)
+		if cost := typedCost(bins, types); ok && cost < cheapestCost {
+			cheapest, cheapestCost = bins, cost
+		}
+	}
+	return cheapest
+}
+
+// fitTyped pack the items in the given order preferring bins of one type,
+// then give each bin the cheapest type it fits in. No bin takes more than
+// maxItems items, when that is set. Returns false if the limits leave too few bins.
+func fitTyped(items Items, order []int, types BinTypes, preferred int, best bool, maxItems Count) ([]typedBin, bool) {
+	used := make([]Count, len(types))
+	bins := make([]typedBin, 0)
+	for _, position := range order {
+		size := Size(items[position])
+		chosen := -1
+		for b, bin := range bins {
+			room := types[bin.kind].Capacity - bin.load
+			if room < size || !bin.hasSlot(maxItems) {
+				continue
+			}
+			if chosen < 0 || (best && room < types[bins[chosen].kind].Capacity-bins[chosen].load) {
+				chosen = b
+			}
+			if !best {
+				break
+			}
+		}
+		if chosen < 0 {
+			kind := preferred
+			if types[kind].Capacity < size || !types.available(kind, used) {
+				kind = types.cheapest(size, used)
+			}
+			if kind < 0 {
+				return nil, false
+			}
+			used[kind]++
+			bins = append(bins, typedBin{kind: kind})
+			chosen = len(bins) - 1
+		}
+		bins[chosen].load += size
+		bins[chosen].items = append(bins[chosen].items, position)
+	}
+
+	// fullest bins first, so they get the pick of the cheap types
+	sorted := make([]int, len(bins))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(a, b int) bool { return bins[sorted[a]].load > bins[sorted[b]].load })
+	for _, b := range sorted {
+		used[bins[b].kind]--
+		if kind := types.cheapest(bins[b].load, used); kind >= 0 && types[kind].Cost < types[bins[b].kind].Cost {
+			bins[b].kind = kind
+		}
+		used[bins[b].kind]++
+	}
+	return bins, true
+}
+
+// typedCost the total cost of the bins
+func typedCost(bins []typedBin, types BinTypes) float64 {
+	var cost float64
+	for _, bin := range bins {
+		cost += types[bin.kind].Cost
+	}
+	return cost
+}
+
+// setTypedBins replace the collection's bins with the given ones
+func (binCollection *BinCollectionImpl) setTypedBins(items Items, bins []typedBin) {
+	binCollection.Bins = make(Bins, len(bins))
+	for i, typed := range bins {
+		bin := NewBin(binCollection.BinTypes[typed.kind].Capacity)
+		bin.Type = typed.kind
+		bin.MaxItems = binCollection.MaxItems
+		for _, position := range typed.items {
+			bin.PackIndex(items[position], position)
+		}
+		binCollection.Bins[i] = bin
+	}
+	binCollection.TotalBins = Count(len(bins))
+}
@@ -0,0 +1,395 @@
+package binpacking
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Vector an amount on each of several dimensions, such as the CPU, memory
+// and disk an item needs or a bin has
+type Vector []Size
+
+// fits whether the vector is no larger than the room on any dimension
+func (vector Vector) fits(room Vector) bool {
+	for d, size := range vector {
+		if size > room[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalized the vector as a fraction of the capacity on each dimension
+func (vector Vector) normalized(capacity Vector) []float64 {
+	fractions := make([]float64, len(vector))
+	for d, size := range vector {
+		fractions[d] = float64(size) / float64(capacity[d])
+	}
+	return fractions
+}
+
+// VectorBin a bin with a capacity on each dimension, which an item fits
+// only if it fits on every dimension
+type VectorBin struct {
+	Capacity Vector   `json:"capacity"`
+	Items    []Vector `json:"items"`
+	// Indices the position of each item in the input passed to PackAll
+	Indices []int  `json:"indices"`
+	Usage   Vector `json:"usage"`
+}
+
+// NewVectorBin create a new bin with the given capacity
+func NewVectorBin(capacity Vector) VectorBin {
+	return VectorBin{capacity, make([]Vector, 0), make([]int, 0), make(Vector, len(capacity))}
+}
+
+// Remaining get the amount of remaining space on each dimension
+func (bin *VectorBin) Remaining() Vector {
+	remaining := make(Vector, len(bin.Capacity))
+	for d, capacity := range bin.Capacity {
+		remaining[d] = capacity - bin.Usage[d]
+	}
+	return remaining
+}
+
+// CanFit check if the given item fits the bin on every dimension
+func (bin *VectorBin) CanFit(item Vector) bool {
+	for d, size := range item {
+		if bin.Usage[d]+size > bin.Capacity[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// Pack adds an item to a VectorBin
+func (bin *VectorBin) Pack(item Vector) {
+	bin.Items = append(bin.Items, item)
+	for d, size := range item {
+		bin.Usage[d] += size
+	}
+}
+
+// PackIndex adds an item to a VectorBin, recording its position in the input
+func (bin *VectorBin) PackIndex(item Vector, index int) {
+	bin.Pack(item)
+	bin.Indices = append(bin.Indices, index)
+}
+
+// ItemOrdering a rule ordering items for the decreasing algorithms,
+// which pack the items with the largest keys first
+type ItemOrdering interface {
+	Key(item, capacity Vector) float64
+}
+
+// ItemOrderingFunc adapter allowing an ordinary function to be used as an ItemOrdering
+type ItemOrderingFunc func(item, capacity Vector) float64
+
+// Key call the underlying function
+func (ordering ItemOrderingFunc) Key(item, capacity Vector) float64 {
+	return ordering(item, capacity)
+}
+
+// BinScoring a rule choosing the bin for an item in the best fit
+// algorithms, which pack it into the bin with the highest score
+type BinScoring interface {
+	Score(item, remaining, capacity Vector) float64
+}
+
+// BinScoringFunc adapter allowing an ordinary function to be used as a BinScoring
+type BinScoringFunc func(item, remaining, capacity Vector) float64
+
+// Score call the underlying function
+func (scoring BinScoringFunc) Score(item, remaining, capacity Vector) float64 {
+	return scoring(item, remaining, capacity)
+}
+
+// itemOrderings the built in orderings, by name. Each works on the
+// item as a fraction of the capacity, so no dimension dominates.
+var itemOrderings = map[string]ItemOrdering{
+	// the dot product with the all ones vector, or the sum over the dimensions
+	"DotProduct": ItemOrderingFunc(func(item, capacity Vector) float64 {
+		var sum float64
+		for _, fraction := range item.normalized(capacity) {
+			sum += fraction
+		}
+		return sum
+	}),
+	"L2Norm": ItemOrderingFunc(func(item, capacity Vector) float64 {
+		var sum float64
+		for _, fraction := range item.normalized(capacity) {
+			sum += fraction * fraction
+		}
+		return math.Sqrt(sum)
+	}),
+	// the dimension the item needs most of
+	"MaxDimension": ItemOrderingFunc(func(item, capacity Vector) float64 {
+		var largest float64
+		for _, fraction := range item.normalized(capacity) {
+			largest = math.Max(largest, fraction)
+		}
+		return largest
+	}),
+}
+
+// binScorings the built in scorings, by name, working on fractions of the capacity
+var binScorings = map[string]BinScoring{
+	// the dot product of the item and the room left, favouring
+	// bins with the most room where the item needs the most
+	"DotProduct": BinScoringFunc(func(item, remaining, capacity Vector) float64 {
+		room := remaining.normalized(capacity)
+		var product float64
+		for d, fraction := range item.normalized(capacity) {
+			product += fraction * room[d]
+		}
+		return product
+	}),
+	// the L2 norm of the room left after packing the item, least first
+	"L2Norm": BinScoringFunc(func(item, remaining, capacity Vector) float64 {
+		room := remaining.normalized(capacity)
+		var sum float64
+		for d, fraction := range item.normalized(capacity) {
+			sum += (room[d] - fraction) * (room[d] - fraction)
+		}
+		return -math.Sqrt(sum)
+	}),
+	// the most room left on any dimension after packing the item, least first
+	"MaxDimension": BinScoringFunc(func(item, remaining, capacity Vector) float64 {
+		room := remaining.normalized(capacity)
+		var largest float64
+		for d, fraction := range item.normalized(capacity) {
+			largest = math.Max(largest, room[d]-fraction)
+		}
+		return -largest
+	}),
+}
+
+// defaultVectorRule the ordering and scoring used when none is named
+const defaultVectorRule = "DotProduct"
+
+// GetItemOrdering get the built in ordering with the given name, nil if there is none
+func GetItemOrdering(name string) ItemOrdering {
+	return itemOrderings[name]
+}
+
+// GetBinScoring get the built in scoring with the given name, nil if there is none
+func GetBinScoring(name string) BinScoring {
+	return binScorings[name]
+}
+
+// VectorRules get the names of the built in orderings and scorings
+func VectorRules() []string {
+	names := make([]string, 0, len(itemOrderings))
+	for name := range itemOrderings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VectorPackingList input for a vector bin packing problem, where
+// items and bins have a size on each of several dimensions
+type VectorPackingList struct {
+	// Capacity the capacity of the bins on each dimension
+	Capacity Vector `json:"capacity"`
+	// Algorithm the algorithm being used to solve the problem
+	Algorithm Algorithm `json:"algorithm"`
+	// Items the actual items being passed in
+	Items []Vector `json:"items"`
+	// Ordering the name of the item ordering for the decreasing algorithms
+	Ordering string `json:"ordering,omitempty"`
+	// Scoring the name of the bin scoring for the best fit algorithms
+	Scoring string `json:"scoring,omitempty"`
+	// Options parameters passed on to the algorithm
+	Options
+}
+
+// VectorPacking an instance of the vector bin packing problem
+type VectorPacking struct {
+	BinCapacity  Vector      `json:"capacity"`
+	TotalBins    Count       `json:"count"`
+	Bins         []VectorBin `json:"bins"`
+	Algorithm    Algorithm   `json:"algorithm"`
+	Ordering     string      `json:"ordering,omitempty"`
+	Scoring      string      `json:"scoring,omitempty"`
+	Status       Status      `json:"status"`
+	SolutionTime int64       `json:"solution_time"`
+	// ItemOrdering used instead of the ordering named, when set
+	ItemOrdering ItemOrdering `json:"-"`
+	// BinScoring used instead of the scoring named, when set
+	BinScoring BinScoring `json:"-"`
+	Options
+}
+
+// vectorPackers the algorithms which can pack vectors
+var vectorPackers = map[Algorithm]struct{ decreasing, best bool }{
+	FirstFit:           {false, false},
+	FirstFitDecreasing: {true, false},
+	BestFit:            {false, true},
+	BestFitDecreasing:  {true, true},
+}
+
+// VectorAlgorithms get the names of the algorithms which can pack vectors
+func VectorAlgorithms() []string {
+	algorithms := make([]Algorithm, 0, len(vectorPackers))
+	for algorithm := range vectorPackers {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool { return algorithms[i] < algorithms[j] })
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = algorithm.String()
+	}
+	return names
+}
+
+// NewVectorPacking create an instance of the vector bin packing
+// problem from a VectorPackingList object
+func NewVectorPacking(list *VectorPackingList) *VectorPacking {
+	return &VectorPacking{
+		BinCapacity: list.Capacity,
+		Bins:        make([]VectorBin, 0),
+		Algorithm:   list.Algorithm,
+		Ordering:    list.Ordering,
+		Scoring:     list.Scoring,
+		Options:     list.Options}
+}
+
+// PackAll pack all of the given items
+func (packing *VectorPacking) PackAll(items []Vector) error {
+	return packing.PackAllContext(context.Background(), items)
+}
+
+// PackAllContext pack all of the given items, giving up when the context
+// is done. Supported algorithms are FirstFit, BestFit and their decreasing
+// versions. The decreasing algorithms take the items in the order of the
+// ItemOrdering, or the one named by Ordering, and the best fit algorithms
+// choose among the bins an item fits by the BinScoring, or the one named by
+// Scoring. Both default to DotProduct.
+func (packing *VectorPacking) PackAllContext(ctx context.Context, items []Vector) error {
+	packer, ok := vectorPackers[packing.Algorithm]
+	if !ok {
+		return &UnknownAlgorithmError{Algorithm: packing.Algorithm}
+	}
+	ordering, scoring := packing.ItemOrdering, packing.BinScoring
+	if ordering == nil {
+		if ordering = GetItemOrdering(orDefault(packing.Ordering)); ordering == nil {
+			return &UnknownRuleError{Rule: packing.Ordering}
+		}
+	}
+	if scoring == nil {
+		if scoring = GetBinScoring(orDefault(packing.Scoring)); scoring == nil {
+			return &UnknownRuleError{Rule: packing.Scoring}
+		}
+	}
+	for _, size := range packing.BinCapacity {
+		if size <= 0 {
+			return &InvalidVectorError{Index: -1, Vector: packing.BinCapacity}
+		}
+	}
+	if len(packing.BinCapacity) == 0 {
+		return &InvalidVectorError{Index: -1, Vector: packing.BinCapacity}
+	}
+	for i, item := range items {
+		if err := validateVector(i, item, packing.BinCapacity); err != nil {
+			return err
+		}
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	if packer.decreasing {
+		keys := make([]float64, len(items))
+		for i, item := range items {
+			keys[i] = ordering.Key(item, packing.BinCapacity)
+		}
+		sort.SliceStable(order, func(a, b int) bool { return keys[order[a]] > keys[order[b]] })
+	}
+	if !packer.best {
+		scoring = nil
+	}
+
+	packing.Bins = make([]VectorBin, 0)
+	packing.Status = Unsolved
+	for _, position := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		packing.place(items[position], position, scoring)
+	}
+	packing.TotalBins = Count(len(packing.Bins))
+	packing.Status = Feasible
+	return nil
+}
+
+// place pack an item into the first bin it fits, or the one it fits
+// with the highest score if there is a scoring, or else a new bin
+func (packing *VectorPacking) place(item Vector, position int, scoring BinScoring) {
+	chosen := -1
+	var best float64
+	for b := range packing.Bins {
+		bin := &packing.Bins[b]
+		if !bin.CanFit(item) {
+			continue
+		}
+		if scoring == nil {
+			chosen = b
+			break
+		}
+		if score := scoring.Score(item, bin.Remaining(), packing.BinCapacity); chosen < 0 || score > best {
+			chosen, best = b, score
+		}
+	}
+	if chosen < 0 {
+		packing.Bins = append(packing.Bins, NewVectorBin(packing.BinCapacity))
+		chosen = len(packing.Bins) - 1
+	}
+	packing.Bins[chosen].PackIndex(item, position)
+}
+
+// orDefault the rule name given, or the default one if none is
+func orDefault(name string) string {
+	if name == "" {
+		return defaultVectorRule
+	}
+	return name
+}
+
+// GetTotalBins getter for the total number of bins
+func (packing *VectorPacking) GetTotalBins() Count {
+	return packing.TotalBins
+}
+
+// String get the JSON representation of the solution
+func (packing *VectorPacking) String() string {
+	jsonString, _ := json.MarshalIndent(packing, "", "  ")
+	return string(jsonString)
+}
+
+// SetTime set the execution time for a single run
+func (packing *VectorPacking) SetTime(nanoseconds int64) {
+	packing.SolutionTime = nanoseconds
+}
+
+// CalculateVectorLowerBound the per-dimension counterpart of
+// CalculateLowerBound: the largest of its bounds for the items' sizes on
+// each dimension alone, as every dimension must fit in the same bins
+func CalculateVectorLowerBound(items []Vector, capacity Vector) Count {
+	best := Count(0)
+	for d, size := range capacity {
+		projected := make(Items, 0, len(items))
+		for _, item := range items {
+			if item[d] > 0 {
+				projected = append(projected, Item(item[d]))
+			}
+		}
+		if bound := CalculateLowerBound(projected, size); bound > best {
+			best = bound
+		}
+	}
+	return best
+}
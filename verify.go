@@ -0,0 +1,293 @@
+package binpacking
+
+// VerificationReport result of independently checking a packing
+// against the problem it claims to solve
+type VerificationReport struct {
+	// Valid true when no problems were found
+	Valid bool `json:"valid"`
+	// MissingItems items in the problem which were not packed
+	MissingItems Items `json:"missingItems,omitempty"`
+	// ExtraItems packed items which are not in the problem, including duplicates
+	ExtraItems Items `json:"extraItems,omitempty"`
+	// OverfullBins indices of bins whose items exceed their capacity,
+	// or which hold more items than the problem's MaxItems
+	OverfullBins []int `json:"overfullBins,omitempty"`
+	// CapacityMismatches indices of bins whose capacity or item limit differs from the problem's
+	CapacityMismatches []int `json:"capacityMismatches,omitempty"`
+	// UsageMismatches indices of bins whose usage is not the sum of their items
+	UsageMismatches []int `json:"usageMismatches,omitempty"`
+	// IndexMismatches indices of bins whose recorded input positions
+	// are missing, reused, or do not hold the packed item
+	IndexMismatches []int `json:"indexMismatches,omitempty"`
+	// MissingIndices input positions of items which were not packed,
+	// for problems whose items are not single sizes
+	MissingIndices []int `json:"missingIndices,omitempty"`
+	// UnsupportedBins indices of containers holding a box which rests on
+	// too little of the boxes below it, when packing boxes
+	UnsupportedBins []int `json:"unsupportedBins,omitempty"`
+	// OverusedTypes indices of bin types used more often than their limit allows
+	OverusedTypes []int `json:"overusedTypes,omitempty"`
+	// ConflictingBins indices of bins holding both items of a conflict
+	ConflictingBins []int `json:"conflictingBins,omitempty"`
+	// TotalCost the total cost claimed by the solution, when packing bins of several types
+	TotalCost float64 `json:"totalCost,omitempty"`
+	// ActualCost the total cost of the bins actually in the solution
+	ActualCost float64 `json:"actualCost,omitempty"`
+	// TotalBins the bin count claimed by the solution
+	TotalBins Count `json:"totalBins"`
+	// ActualBins the number of bins actually in the solution
+	ActualBins Count `json:"actualBins"`
+}
+
+// Verify check that a solution packs exactly the items of the packing list,
+// that no bin is over capacity, and that the solution's bookkeeping is consistent.
+// When the list has BinTypes, each bin must have the capacity of its type, no
+// type may be used more than its limit, and the total cost must add up.
+// When it has Conflicts, no bin may hold both items of any of them, and
+// when it has MaxItems, every bin must carry that limit and keep to it.
+func Verify(list *PackingList, sol *BinCollectionImpl) *VerificationReport {
+	report := &VerificationReport{
+		TotalBins:  sol.TotalBins,
+		TotalCost:  sol.TotalCost,
+		ActualBins: Count(len(sol.Bins))}
+	used := make([]Count, len(list.BinTypes))
+
+	// count each item size in the problem, then remove everything packed
+	remaining := make(map[Item]int)
+	for _, item := range list.Items {
+		remaining[item]++
+	}
+	usedIndices := make([]bool, len(list.Items))
+	for i, bin := range sol.Bins {
+		if !validIndices(list.Items, bin, usedIndices) {
+			report.IndexMismatches = append(report.IndexMismatches, i)
+		}
+		var sum Size
+		for _, item := range bin.Items {
+			sum += Size(item)
+			if remaining[item] > 0 {
+				remaining[item]--
+			} else {
+				report.ExtraItems = append(report.ExtraItems, item)
+			}
+		}
+		if len(list.BinTypes) == 0 {
+			if bin.Capacity != list.Size || bin.MaxItems != list.MaxItems {
+				report.CapacityMismatches = append(report.CapacityMismatches, i)
+			}
+		} else if bin.Type < 0 || bin.Type >= len(list.BinTypes) || bin.Capacity != list.BinTypes[bin.Type].Capacity ||
+			bin.MaxItems != list.MaxItems {
+			report.CapacityMismatches = append(report.CapacityMismatches, i)
+		} else {
+			used[bin.Type]++
+			report.ActualCost += list.BinTypes[bin.Type].Cost
+		}
+		if sum != bin.Usage {
+			report.UsageMismatches = append(report.UsageMismatches, i)
+		}
+		if sum > bin.Capacity || (list.MaxItems > 0 && Count(len(bin.Items)) > list.MaxItems) {
+			report.OverfullBins = append(report.OverfullBins, i)
+		}
+	}
+	for kind, binType := range list.BinTypes {
+		if binType.Limit > 0 && used[kind] > binType.Limit {
+			report.OverusedTypes = append(report.OverusedTypes, kind)
+		}
+	}
+	report.ConflictingBins = conflictingBins(list.Conflicts, sol.Bins, len(list.Items))
+	// walk the input again so missing items are reported in input order
+	for _, item := range list.Items {
+		if remaining[item] > 0 {
+			report.MissingItems = append(report.MissingItems, item)
+			remaining[item]--
+		}
+	}
+
+	report.Valid = len(report.MissingItems) == 0 &&
+		len(report.ExtraItems) == 0 &&
+		len(report.OverfullBins) == 0 &&
+		len(report.CapacityMismatches) == 0 &&
+		len(report.UsageMismatches) == 0 &&
+		len(report.IndexMismatches) == 0 &&
+		len(report.OverusedTypes) == 0 &&
+		len(report.ConflictingBins) == 0 &&
+		report.TotalCost == report.ActualCost &&
+		report.TotalBins == report.ActualBins
+	return report
+}
+
+// validIndices check that a bin records one unused input position per item,
+// each holding an item of the same size, marking those positions as used
+func validIndices(input Items, bin Bin, used []bool) bool {
+	if len(bin.Indices) != len(bin.Items) {
+		return false
+	}
+	valid := true
+	for j, index := range bin.Indices {
+		if index < 0 || index >= len(input) || used[index] || input[index] != bin.Items[j] {
+			valid = false
+			continue
+		}
+		used[index] = true
+	}
+	return valid
+}
+
+// conflictingBins the indices of the bins which, by the input positions
+// they record, hold both items of some conflict, in increasing order
+func conflictingBins(conflicts Conflicts, bins Bins, itemCount int) []int {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	binsOf := make([][]int, itemCount) // a position may be recorded by several bins
+	for i, bin := range bins {
+		for _, index := range bin.Indices {
+			if index >= 0 && index < itemCount {
+				binsOf[index] = append(binsOf[index], i)
+			}
+		}
+	}
+	conflicting := make(map[int]bool)
+	for _, conflict := range conflicts {
+		if conflict[0] < 0 || conflict[0] >= itemCount || conflict[1] < 0 || conflict[1] >= itemCount {
+			continue
+		}
+		for _, first := range binsOf[conflict[0]] {
+			for _, second := range binsOf[conflict[1]] {
+				if first == second {
+					conflicting[first] = true
+				}
+			}
+		}
+	}
+	var indices []int
+	for i := range bins {
+		if conflicting[i] {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// VerifyCuttingStock check that a cutting stock solution's patterns hold
+// exactly the items demanded, that no pattern is over capacity or holds
+// more than MaxItems items, and that the solution's bookkeeping is
+// consistent. Bins are identified by the index of their pattern.
+func VerifyCuttingStock(list *CuttingStockList, sol *CuttingStock) *VerificationReport {
+	report := &VerificationReport{TotalBins: sol.TotalBins}
+
+	remaining := make(map[Size]int)
+	for _, demand := range list.Demands {
+		remaining[demand.Size] += int(demand.Quantity)
+	}
+	for i, pattern := range sol.Patterns {
+		report.ActualBins += pattern.Quantity
+		var sum Size
+		var count Count
+		for _, demand := range pattern.Items {
+			sum += demand.Size * Size(demand.Quantity)
+			count += demand.Quantity
+			remaining[demand.Size] -= int(demand.Quantity) * int(pattern.Quantity)
+		}
+		if sol.BinCapacity != list.Size || sol.MaxItems != list.MaxItems {
+			report.CapacityMismatches = append(report.CapacityMismatches, i)
+		}
+		if sum != pattern.Usage {
+			report.UsageMismatches = append(report.UsageMismatches, i)
+		}
+		if sum > list.Size || (list.MaxItems > 0 && count > list.MaxItems) {
+			report.OverfullBins = append(report.OverfullBins, i)
+		}
+	}
+	// walk the demands and patterns again so items are reported in order
+	for _, demand := range list.Demands {
+		for ; remaining[demand.Size] > 0; remaining[demand.Size]-- {
+			report.MissingItems = append(report.MissingItems, Item(demand.Size))
+		}
+	}
+	for _, pattern := range sol.Patterns {
+		for _, demand := range pattern.Items {
+			for ; remaining[demand.Size] < 0; remaining[demand.Size]++ {
+				report.ExtraItems = append(report.ExtraItems, Item(demand.Size))
+			}
+		}
+	}
+
+	report.Valid = len(report.MissingItems) == 0 &&
+		len(report.ExtraItems) == 0 &&
+		len(report.OverfullBins) == 0 &&
+		len(report.CapacityMismatches) == 0 &&
+		len(report.UsageMismatches) == 0 &&
+		report.TotalBins == report.ActualBins
+	return report
+}
+
+// VerifyVector check that a vector packing holds each item of the list once,
+// at the position it records, that no bin is over capacity on any dimension,
+// and that the solution's bookkeeping is consistent
+func VerifyVector(list *VectorPackingList, sol *VectorPacking) *VerificationReport {
+	report := &VerificationReport{
+		TotalBins:  sol.TotalBins,
+		ActualBins: Count(len(sol.Bins))}
+	used := make([]bool, len(list.Items))
+	for i, bin := range sol.Bins {
+		valid := len(bin.Indices) == len(bin.Items)
+		sum := make(Vector, len(list.Capacity))
+		for j, item := range bin.Items {
+			if len(item) != len(sum) {
+				valid = false
+				continue
+			}
+			for d, size := range item {
+				sum[d] += size
+			}
+			if j >= len(bin.Indices) {
+				continue
+			}
+			index := bin.Indices[j]
+			if index < 0 || index >= len(list.Items) || used[index] || !equalVectors(list.Items[index], item) {
+				valid = false
+				continue
+			}
+			used[index] = true
+		}
+		if !valid {
+			report.IndexMismatches = append(report.IndexMismatches, i)
+		}
+		if !equalVectors(bin.Capacity, list.Capacity) {
+			report.CapacityMismatches = append(report.CapacityMismatches, i)
+		}
+		if !equalVectors(sum, bin.Usage) {
+			report.UsageMismatches = append(report.UsageMismatches, i)
+		}
+		if !sum.fits(list.Capacity) {
+			report.OverfullBins = append(report.OverfullBins, i)
+		}
+	}
+	for index, packed := range used {
+		if !packed {
+			report.MissingIndices = append(report.MissingIndices, index)
+		}
+	}
+
+	report.Valid = len(report.MissingIndices) == 0 &&
+		len(report.OverfullBins) == 0 &&
+		len(report.CapacityMismatches) == 0 &&
+		len(report.UsageMismatches) == 0 &&
+		len(report.IndexMismatches) == 0 &&
+		report.TotalBins == report.ActualBins
+	return report
+}
+
+// equalVectors whether two vectors have the same size on every dimension
+func equalVectors(first, second Vector) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for d := range first {
+		if first[d] != second[d] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package binpacking
+
+// WorstFitPack pack a single item using the worst fit algorithm
+func WorstFitPack(binCollection *BinCollectionImpl, item Item) {
+
+	// find the bin with the largest remaining space after adding the item
+	largestRemainderIndex := -1
+	var largestRemainder Size
+	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
+		remainder := binCollection.binAt(i).Remaining() - Size(item)
+		if binCollection.binAt(i).CanFit(item) && (largestRemainderIndex < 0 || remainder > largestRemainder) {
+			largestRemainder = remainder
+			largestRemainderIndex = i
+		}
+	}
+	// if we found a bin that the item will fit inside
+	if largestRemainderIndex >= 0 {
+		binCollection.binAt(largestRemainderIndex).Pack(item)
+	} else {
+		// create a new bin
+		newBin := binCollection.NewBin()
+		newBin.Pack(item)
+	}
+
+}
+
+// WorstFitDecreasingPack pack the next item using the worst fit decreasing algorithm
+func WorstFitDecreasingPack(binCollection *BinCollectionImpl, item Item) {
+	WorstFitPack(binCollection, item)
+}
+
+// AlmostWorstFitPack pack a single item using the almost worst fit algorithm,
+// which puts objects in the bin with the second largest remaining space
+// that can fit them, or the only such bin if there is just one
+func AlmostWorstFitPack(binCollection *BinCollectionImpl, item Item) {
+
+	// track the two bins with the largest remaining space after adding the item
+	largestIndex, secondIndex := -1, -1
+	var largest, second Size
+	for i := 0; i < int(binCollection.GetTotalBins()); i++ {
+		remainder := binCollection.binAt(i).Remaining() - Size(item)
+		if !binCollection.binAt(i).CanFit(item) {
+			continue
+		}
+		if largestIndex < 0 || remainder > largest {
+			second, secondIndex = largest, largestIndex
+			largest, largestIndex = remainder, i
+		} else if secondIndex < 0 || remainder > second {
+			second, secondIndex = remainder, i
+		}
+	}
+	if secondIndex >= 0 {
+		binCollection.binAt(secondIndex).Pack(item)
+	} else if largestIndex >= 0 {
+		binCollection.binAt(largestIndex).Pack(item)
+	} else {
+		// create a new bin
+		newBin := binCollection.NewBin()
+		newBin.Pack(item)
+	}
+
+}